@@ -0,0 +1,35 @@
+package logx
+
+import (
+	"runtime"
+	"strings"
+)
+
+// logxPackagePrefix is the prefix of the names of the functions in this package.
+const logxPackagePrefix = "github.com/cmstar/go-logx."
+
+// maxCallerDepth is the maximum number of frames walked when finding the caller.
+const maxCallerDepth = 32
+
+// callerFrame returns the first frame on the stack of the calling goroutine that is outside of this package.
+// The frame PC is 0 if there is no such frame.
+func callerFrame() runtime.Frame {
+	pcs := make([]uintptr, maxCallerDepth)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !isInternalFrame(f) {
+			return f
+		}
+
+		if !more {
+			return runtime.Frame{}
+		}
+	}
+}
+
+// isInternalFrame reports whether the frame belongs to the Go runtime or this package.
+func isInternalFrame(f runtime.Frame) bool {
+	return strings.HasPrefix(f.Function, "runtime.") || strings.HasPrefix(f.Function, logxPackagePrefix)
+}
@@ -0,0 +1,80 @@
+package logx
+
+import "context"
+
+// contextKey is the key of the Logger stored in a context.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx which carries the given Logger, the Logger can be retrieved by FromContext().
+func NewContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the Logger carried by ctx, which is stored by NewContext().
+// Returns nil if there is none. Op() can be used to get a LoggerOp safely, e.g.
+//
+//	logx.Op(logx.FromContext(ctx)).Info("msg")
+func FromContext(ctx context.Context) Logger {
+	if ctx == nil {
+		return nil
+	}
+	logger, _ := ctx.Value(contextKey{}).(Logger)
+	return logger
+}
+
+// With wraps the given Logger, returns a new Logger which adds the given key-values to each log message,
+// before the key-values of the message.
+// Calling With() on the returned Logger accumulates the key-values.
+//
+// The key-values are copied. If they are unpaired, the last element gets the key 'UNKNOWN',
+// so that the key-values of messages stay aligned.
+func With(logger Logger, keyValues ...interface{}) Logger {
+	if len(keyValues) == 0 {
+		return logger
+	}
+
+	var bound []interface{}
+	if w, ok := logger.(*withLogger); ok {
+		logger = w.logger
+		bound = w.keyValues
+	}
+
+	kv := make([]interface{}, 0, len(bound)+len(keyValues)+1)
+	kv = append(kv, bound...)
+	kv = appendPaired(kv, keyValues)
+	return &withLogger{logger, kv}
+}
+
+// withLogger is a Logger which adds key-values to log messages.
+type withLogger struct {
+	logger    Logger
+	keyValues []interface{}
+}
+
+func (l *withLogger) Log(level Level, message string, keyValues ...interface{}) error {
+	return l.logger.Log(level, message, l.join(keyValues)...)
+}
+
+func (l *withLogger) LogFn(level Level, messageFactory func() (message string, keyValues []interface{})) error {
+	return l.logger.LogFn(level, func() (string, []interface{}) {
+		message, keyValues := messageFactory()
+		return message, l.join(keyValues)
+	})
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (l *withLogger) Unwrap() Logger {
+	return l.logger
+}
+
+// Enabled implements LevelEnabler.Enabled(), the wrapped Logger decides.
+func (l *withLogger) Enabled(level Level) bool {
+	return Enabled(l.logger, level)
+}
+
+// join returns the bound key-values followed by the given ones.
+func (l *withLogger) join(keyValues []interface{}) []interface{} {
+	res := make([]interface{}, 0, len(l.keyValues)+len(keyValues))
+	res = append(res, l.keyValues...)
+	return append(res, keyValues...)
+}
@@ -0,0 +1,52 @@
+package logx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	r := logxtest.NewRecorder()
+	ctx := logx.NewContext(context.Background(), r)
+
+	a := assert.New(t)
+	a.Same(r, logx.FromContext(ctx))
+	a.Nil(logx.FromContext(context.Background()))
+}
+
+func TestWith(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.With(r, "a", 1)
+	l2 := logx.With(l, "b", 2)
+
+	l.Log(logx.LevelInfo, "m1", "k", "v")
+	l2.Log(logx.LevelInfo, "m2")
+	l2.LogFn(logx.LevelWarn, func() (string, []interface{}) { return "m3", []interface{}{"k", "v"} })
+
+	a := assert.New(t)
+	a.Equal(`INFO m1 a=1 k=v
+INFO m2 a=1 b=2
+WARN m3 a=1 b=2 k=v
+`, r.String())
+	a.Same(r, logx.With(r))
+	a.Equal(r, l2.(logx.Unwrapper).Unwrap())
+	a.False(logx.Enabled(logx.With(logx.NopLogger, "a", 1), logx.LevelInfo))
+}
+
+func TestWith_copyAndPad(t *testing.T) {
+	r := logxtest.NewRecorder()
+	kv := []interface{}{"a", 1, "b"}
+	l := logx.With(r, kv...)
+	kv[0] = "x"
+
+	l.Log(logx.LevelInfo, "m1", "k", "v")
+	logx.With(l, "c").Log(logx.LevelInfo, "m2")
+
+	assert.Equal(t, `INFO m1 a=1 UNKNOWN=b k=v
+INFO m2 a=1 UNKNOWN=b UNKNOWN=c
+`, r.String())
+}
@@ -0,0 +1,233 @@
+package logx
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default values of DedupConfig.
+const (
+	defaultDedupWindow     = 10 * time.Second
+	defaultDedupMaxEntries = 1000
+)
+
+// DedupConfig configures the Logger created by Dedup.
+type DedupConfig struct {
+	// Window is the period, starting from the first occurrence of an entry, in which identical entries are collapsed.
+	// If it is not positive, 10 seconds is used.
+	Window time.Duration
+
+	// MaxEntries limits the number of entries remembered. The least recently used entry is forgotten
+	// when the limit is exceeded. If it is not positive, 1000 is used.
+	MaxEntries int
+
+	// Now returns the current time. If it is nil, time.Now is used.
+	// It is useful for testing.
+	Now func() time.Time
+}
+
+// Dedup wraps the given Logger, returns a new Logger which suppresses duplicated log messages.
+// Log messages with the same level, message and key-values are identical; the first one is logged,
+// the identical ones within the window are dropped and counted.
+//
+// The count is reported by a follow-up message, which is the first one with an extra key-value pair repeated=N,
+// it is logged on the first call of Log() or LogFn() after the window ends, whichever message is logged,
+// or when the entry is forgotten. There is no background timer: if no more messages are logged, the follow-up
+// messages remain pending until Flush() is called.
+// The returned Logger implements Flusher, its Flush() logs all pending follow-up messages; call Flush(logger)
+// before the program exits.
+//
+// A failure of logging a follow-up message does not prevent the current message from being logged,
+// the errors are combined.
+//
+// The LogFn factory is always invoked if the level is enabled on the given Logger, since the message is needed
+// for comparing.
+func Dedup(raw Logger, config DedupConfig) Logger {
+	if config.Window <= 0 {
+		config.Window = defaultDedupWindow
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = defaultDedupMaxEntries
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &dedupLogger{
+		logger:  raw,
+		config:  config,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		windows: list.New(),
+	}
+}
+
+// dedupLogger is a Logger which suppresses duplicated log messages.
+type dedupLogger struct {
+	logger Logger
+	config DedupConfig
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // Maps fingerprints to the elements of lru.
+	lru     *list.List               // Elements are *dedupEntry, the most recently used is at the front.
+	windows *list.List               // Elements are *dedupEntry with open windows, ordered by the start time.
+}
+
+// dedupEntry is a remembered log message.
+type dedupEntry struct {
+	fingerprint string
+	level       Level
+	message     string
+	keyValues   []interface{} // A copy of the key-values of the first message.
+	start       time.Time     // The time when the window starts.
+	repeated    int           // The number of messages dropped in the window.
+	window      *list.Element // The element in dedupLogger.windows, nil if the window has ended.
+}
+
+// followUp returns the arguments of the follow-up message, ok is false if there is no need to log it.
+func (e *dedupEntry) followUp() (level Level, message string, keyValues []interface{}, ok bool) {
+	if e.repeated == 0 {
+		return
+	}
+
+	keyValues = make([]interface{}, 0, len(e.keyValues)+2)
+	keyValues = append(keyValues, e.keyValues...)
+	keyValues = append(keyValues, "repeated", e.repeated)
+	return e.level, e.message, keyValues, true
+}
+
+func (l *dedupLogger) Log(level Level, message string, keyValues ...interface{}) error {
+	now := l.config.Now()
+	fingerprint := l.fingerprint(level, message, keyValues)
+
+	l.mu.Lock()
+	pending := l.expire(now, nil)
+	if elem, ok := l.entries[fingerprint]; ok {
+		l.lru.MoveToFront(elem)
+		e := elem.Value.(*dedupEntry)
+
+		if e.window != nil && now.Sub(e.start) < l.config.Window {
+			e.repeated++
+			l.mu.Unlock()
+			return l.logFollowUps(pending)
+		}
+
+		// The window ends, start a new one.
+		pending = l.endWindow(e, pending)
+		e.start = now
+		e.window = l.windows.PushBack(e)
+	} else {
+		e := &dedupEntry{
+			fingerprint: fingerprint,
+			level:       level,
+			message:     message,
+			keyValues:   append([]interface{}(nil), keyValues...),
+			start:       now,
+		}
+		e.window = l.windows.PushBack(e)
+		l.entries[fingerprint] = l.lru.PushFront(e)
+
+		for l.lru.Len() > l.config.MaxEntries {
+			oldest := l.lru.Remove(l.lru.Back()).(*dedupEntry)
+			delete(l.entries, oldest.fingerprint)
+			pending = l.endWindow(oldest, pending)
+		}
+	}
+	l.mu.Unlock()
+
+	followUpErr := l.logFollowUps(pending)
+	return joinErrors(followUpErr, l.logger.Log(level, message, keyValues...))
+}
+
+// expire ends the windows which have ended by now, appends the entries having dropped messages to pending.
+// l.mu must be held.
+func (l *dedupLogger) expire(now time.Time, pending []*dedupEntry) []*dedupEntry {
+	for elem := l.windows.Front(); elem != nil; elem = l.windows.Front() {
+		e := elem.Value.(*dedupEntry)
+		if now.Sub(e.start) < l.config.Window {
+			break
+		}
+		pending = l.endWindow(e, pending)
+	}
+	return pending
+}
+
+// endWindow ends the window of the entry, appends a snapshot of it to pending if it has dropped messages.
+// l.mu must be held.
+func (l *dedupLogger) endWindow(e *dedupEntry, pending []*dedupEntry) []*dedupEntry {
+	if e.window != nil {
+		l.windows.Remove(e.window)
+		e.window = nil
+	}
+
+	if e.repeated > 0 {
+		snapshot := *e
+		pending = append(pending, &snapshot)
+		e.repeated = 0
+	}
+	return pending
+}
+
+func (l *dedupLogger) LogFn(level Level, messageFactory func() (message string, keyValues []interface{})) error {
+	if !Enabled(l.logger, level) {
+		return nil
+	}
+
+	message, keyValues := messageFactory()
+	return l.Log(level, message, keyValues...)
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (l *dedupLogger) Unwrap() Logger {
+	return l.logger
+}
+
+// Enabled implements LevelEnabler.Enabled(), the wrapped Logger decides.
+func (l *dedupLogger) Enabled(level Level) bool {
+	return Enabled(l.logger, level)
+}
+
+// Flush logs the follow-up messages of all entries which have dropped messages.
+func (l *dedupLogger) Flush() error {
+	var pending []*dedupEntry
+
+	l.mu.Lock()
+	for elem := l.lru.Front(); elem != nil; elem = elem.Next() {
+		e := elem.Value.(*dedupEntry)
+		if e.repeated > 0 {
+			snapshot := *e
+			pending = append(pending, &snapshot)
+			e.repeated = 0
+		}
+	}
+	l.mu.Unlock()
+
+	return l.logFollowUps(pending)
+}
+
+// logFollowUps logs the follow-up messages of the given entries, returns the errors combined.
+func (l *dedupLogger) logFollowUps(entries []*dedupEntry) error {
+	var errs []error
+	for _, e := range entries {
+		level, message, keyValues, ok := e.followUp()
+		if !ok {
+			continue
+		}
+		errs = append(errs, l.logger.Log(level, message, keyValues...))
+	}
+	return joinErrors(errs...)
+}
+
+// fingerprint returns a string which identifies the log message.
+// The types of the keys and values are included, so that values of different types with the same text,
+// such as 1 and "1", are not taken as identical.
+func (l *dedupLogger) fingerprint(level Level, message string, keyValues []interface{}) string {
+	b := new(strings.Builder)
+	fmt.Fprintf(b, "%d\x00%s", level, message)
+	for _, v := range keyValues {
+		fmt.Fprintf(b, "\x00%T\x00%v", v, v)
+	}
+	return b.String()
+}
@@ -0,0 +1,181 @@
+package logx_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedup(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := logxtest.NewRecorder()
+	l := logx.Dedup(r, logx.DedupConfig{
+		Window: time.Second,
+		Now:    func() time.Time { return now },
+	})
+
+	l.Log(logx.LevelError, "e", "k", 1)
+	l.Log(logx.LevelError, "e", "k", 1)
+	l.Log(logx.LevelError, "e", "k", 2)
+	l.Log(logx.LevelWarn, "e", "k", 1)
+	l.LogFn(logx.LevelError, func() (string, []interface{}) { return "e", []interface{}{"k", 1} })
+
+	now = now.Add(time.Second)
+	l.Log(logx.LevelError, "e", "k", 1)
+	l.Log(logx.LevelError, "e", "k", 1)
+	l.Log(logx.LevelError, "e", "k", 2)
+
+	assert.Equal(t, `ERROR e k=1
+ERROR e k=2
+WARN e k=1
+ERROR e k=1 repeated=2
+ERROR e k=1
+ERROR e k=2
+`, r.String())
+
+	r.Messages = nil
+	l.(logx.Flusher).Flush()
+	assert.Equal(t, "ERROR e k=1 repeated=1\n", r.String())
+
+	r.Messages = nil
+	l.(logx.Flusher).Flush()
+	assert.Equal(t, "", r.String())
+}
+
+func TestDedup_types(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.Dedup(r, logx.DedupConfig{Window: time.Second})
+
+	l.Log(logx.LevelInfo, "m", "k", 1)
+	l.Log(logx.LevelInfo, "m", "k", "1")
+	l.Log(logx.LevelInfo, "m", "k", int64(1))
+	assert.Equal(t, 3, len(r.Messages))
+}
+
+func TestDedup_expire(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := logxtest.NewRecorder()
+	l := logx.Dedup(r, logx.DedupConfig{
+		Window: time.Second,
+		Now:    func() time.Time { return now },
+	})
+
+	kv := []interface{}{"k", 1}
+	l.Log(logx.LevelError, "a", kv...)
+	l.Log(logx.LevelError, "a", "k", 1)
+	l.Log(logx.LevelError, "b")
+	l.Log(logx.LevelError, "b")
+
+	r.Messages = nil
+	kv[1] = 2 // The entry keeps a copy.
+	now = now.Add(time.Second)
+	l.Log(logx.LevelInfo, "c")
+	l.Log(logx.LevelInfo, "c")
+
+	assert.Equal(t, `ERROR a k=1 repeated=1
+ERROR b repeated=1
+INFO c
+`, r.String())
+}
+
+func TestDedup_followUpError(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := logxtest.NewRecorder()
+	l := logx.Dedup(failFollowUps{r}, logx.DedupConfig{
+		Window: time.Second,
+		Now:    func() time.Time { return now },
+	})
+
+	l.Log(logx.LevelInfo, "a")
+	l.Log(logx.LevelInfo, "a")
+	l.Log(logx.LevelInfo, "b")
+	l.Log(logx.LevelInfo, "b")
+
+	now = now.Add(time.Second)
+	err := l.Log(logx.LevelInfo, "c")
+
+	a := assert.New(t)
+	a.EqualError(err, "follow-up; follow-up")
+	a.Equal("INFO a\nINFO b\nINFO c\n", r.String())
+}
+
+func TestDedup_maxEntries(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.Dedup(r, logx.DedupConfig{MaxEntries: 2})
+
+	l.Log(logx.LevelInfo, "a")
+	l.Log(logx.LevelInfo, "a")
+	l.Log(logx.LevelInfo, "b")
+	l.Log(logx.LevelInfo, "a") // 'a' becomes the most recently used.
+	l.Log(logx.LevelInfo, "c") // 'b' is forgotten.
+	l.Log(logx.LevelInfo, "b") // 'a' is forgotten.
+
+	assert.Equal(t, `INFO a
+INFO b
+INFO c
+INFO a repeated=2
+INFO b
+`, r.String())
+}
+
+func TestDedup_concurrent(t *testing.T) {
+	var mu sync.Mutex
+	r := logxtest.NewRecorder()
+	l := logx.Dedup(lockedLogger{&mu, r}, logx.DedupConfig{Window: time.Hour})
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Log(logx.LevelInfo, "m")
+			}
+		}()
+	}
+	wg.Wait()
+	l.(logx.Flusher).Flush()
+
+	assert.Equal(t, "INFO m\nINFO m repeated=999\n", r.String())
+}
+
+// failFollowUps fails on the follow-up messages of Dedup, logs other messages to the wrapped Logger.
+type failFollowUps struct {
+	logger logx.Logger
+}
+
+func (l failFollowUps) Log(level logx.Level, message string, keyValues ...interface{}) error {
+	for i := 0; i < len(keyValues); i += 2 {
+		if keyValues[i] == "repeated" {
+			return errors.New("follow-up")
+		}
+	}
+	return l.logger.Log(level, message, keyValues...)
+}
+
+func (l failFollowUps) LogFn(level logx.Level, messageFactory func() (string, []interface{})) error {
+	message, keyValues := messageFactory()
+	return l.Log(level, message, keyValues...)
+}
+
+// lockedLogger makes a Logger safe for concurrent use.
+type lockedLogger struct {
+	mu     *sync.Mutex
+	logger logx.Logger
+}
+
+func (l lockedLogger) Log(level logx.Level, message string, keyValues ...interface{}) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.logger.Log(level, message, keyValues...)
+}
+
+func (l lockedLogger) LogFn(level logx.Level, messageFactory func() (string, []interface{})) error {
+	message, keyValues := messageFactory()
+	return l.Log(level, message, keyValues...)
+}
@@ -0,0 +1,23 @@
+package logx
+
+// LevelEnabler can be implemented by Loggers to report whether a level is enabled, so callers can avoid
+// building expensive log messages which would be dropped.
+// It is optional, use the function Enabled() to query a Logger.
+type LevelEnabler interface {
+	// Enabled returns false if log messages at the given level are sure to be dropped.
+	Enabled(level Level) bool
+}
+
+// Enabled reports whether the given level is enabled on the Logger.
+// If the Logger implements LevelEnabler, returns the result of its Enabled(); otherwise returns true.
+// Returns false if the Logger is nil.
+func Enabled(logger Logger, level Level) bool {
+	if logger == nil {
+		return false
+	}
+
+	if e, ok := logger.(LevelEnabler); ok {
+		return e.Enabled(level)
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package logx_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnabled(t *testing.T) {
+	r := logxtest.NewRecorder()
+	filter := logx.FilterLevel(r, logx.LevelBeyondWarn)
+
+	a := assert.New(t)
+	a.False(logx.Enabled(nil, logx.LevelInfo))
+	a.True(logx.Enabled(r, logx.LevelInfo))
+	a.False(logx.Enabled(logx.NopLogger, logx.LevelFatal))
+	a.True(logx.Enabled(logx.NewStdLogger(nil), logx.LevelTrace))
+
+	a.False(logx.Enabled(filter, logx.LevelInfo))
+	a.True(logx.Enabled(filter, logx.LevelWarn))
+	a.False(logx.Enabled(logx.FilterLevel(logx.NopLogger, logx.LevelBeyondWarn), logx.LevelWarn))
+
+	// Wrappers ask the wrapped Logger.
+	a.False(logx.Enabled(logx.RateLimit(filter, logx.RateLimitConfig{}), logx.LevelInfo))
+	a.False(logx.Enabled(logx.SampleRatio(filter, logx.RatioSampleConfig{}), logx.LevelInfo))
+	a.False(logx.Enabled(logx.SampleFirstN(filter, logx.FirstNSampleConfig{}), logx.LevelInfo))
+	a.False(logx.Enabled(logx.Dedup(filter, logx.DedupConfig{}), logx.LevelInfo))
+	a.False(logx.Enabled(logx.Redact(filter, logx.RedactConfig{}), logx.LevelInfo))
+	a.True(logx.Enabled(logx.Redact(filter, logx.RedactConfig{}), logx.LevelError))
+
+	// Predicates not needing the message decide by the level.
+	a.False(logx.Enabled(logx.FilterBy(r, logx.LevelPredicate(logx.LevelError)), logx.LevelInfo))
+	a.True(logx.Enabled(logx.FilterBy(r, logx.LevelPredicate(logx.LevelError)), logx.LevelError))
+	a.True(logx.Enabled(logx.FilterBy(r, logx.IncludeMessage(regexp.MustCompile("x"))), logx.LevelInfo))
+	a.False(logx.Enabled(logx.FilterBy(filter, logx.IncludeMessage(regexp.MustCompile("x"))), logx.LevelInfo))
+
+	// HandlerLogger asks the Handler.
+	a.True(logx.Enabled(logx.NewHandlerLogger("", r), logx.LevelInfo))
+	a.False(logx.Enabled(logx.NewHandlerLogger("", logx.LoggerHandler(filter)), logx.LevelInfo))
+	a.True(logx.Enabled(logx.NewHandlerLogger("", logx.LoggerHandler(filter)), logx.LevelError))
+}
+
+func TestLogManager_Enabled(t *testing.T) {
+	m := logx.NewManager()
+	m.Set("a", logx.FilterLevel(logxtest.NewRecorder(), logx.LevelError))
+
+	a := assert.New(t)
+	a.False(m.Enabled("", logx.LevelError))
+	a.True(m.Enabled("a.b", logx.LevelError))
+	a.False(m.Enabled("a.b", logx.LevelInfo))
+}
+
+// countingStringer counts the calls of String().
+type countingStringer struct {
+	n *int
+}
+
+func (s countingStringer) String() string {
+	*s.n++
+	return "s"
+}
+
+func TestLoggerOp_Enabled(t *testing.T) {
+	r := logxtest.NewRecorder()
+	op := logx.Op(logx.FilterLevel(r, logx.LevelError))
+
+	n := 0
+	s := countingStringer{&n}
+	op.Tracef("%v", s)
+	op.Debugf("%v", s)
+	op.Infof("%v", s)
+	op.Warnf("%v", s)
+	op.Fatalf("%v", s)
+	op.Loglf(logx.LevelInfo, "%v", s)
+	op.Errorf("%v", s)
+
+	a := assert.New(t)
+	a.Equal(1, n)
+	a.Equal("ERROR s\n", r.String())
+	a.True(op.Enabled(logx.LevelError))
+	a.False(op.Enabled(logx.LevelInfo))
+	a.True(logx.Enabled(op, logx.LevelError))
+}
+
+func TestHandlerLogger_LogFn_disabled(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.NewHandlerLogger("", logx.LoggerHandler(logx.NopLogger))
+
+	l.LogFn(logx.LevelInfo, func() (string, []interface{}) {
+		t.Fatal("the factory should not be invoked")
+		return "", nil
+	})
+	assert.Equal(t, 0, len(r.Messages))
+}
+
+func TestWrappers_LogFn_disabled(t *testing.T) {
+	loggers := []logx.Logger{
+		logx.Dedup(logx.NopLogger, logx.DedupConfig{}),
+		logx.SampleFirstN(logx.NopLogger, logx.FirstNSampleConfig{}),
+		logx.Filter(logx.NopLogger, func(logx.Entry) bool { return true }),
+	}
+
+	for _, l := range loggers {
+		l.LogFn(logx.LevelInfo, func() (string, []interface{}) {
+			t.Fatal("the factory should not be invoked")
+			return "", nil
+		})
+	}
+}
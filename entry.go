@@ -0,0 +1,40 @@
+package logx
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// Entry is a log message.
+//
+// Entries created by HandlerLogger have all fields set (Caller only when enabled);
+// entries created by other functions, such as the ones passed to Predicate.Match, may set only
+// Level, Message and KeyValues.
+type Entry struct {
+	Time      time.Time       // The time when the log message is created.
+	Level     Level           // The level of the log message.
+	Message   string          // The message.
+	KeyValues []interface{}   // The key-value pairs, in the same form of the keyValues parameter of Logger.Log().
+	Name      string          // The name of the logger, can be empty.
+	Caller    runtime.Frame   // The caller of the logging function, its PC is 0 if the caller is not captured.
+	Context   context.Context // The context bound to the logger, can be nil.
+}
+
+// Value returns the value of the given key in KeyValues; ok is false if the key does not exist.
+// Keys are compared with their string representation given by fmt.Sprint().
+// The last element of unpaired KeyValues is treated as a value with key 'UNKNOWN'.
+func (e Entry) Value(key string) (value interface{}, ok bool) {
+	length := len(e.KeyValues)
+	for i := 0; i < length-1; i += 2 {
+		if fmt.Sprint(e.KeyValues[i]) == key {
+			return e.KeyValues[i+1], true
+		}
+	}
+
+	if length%2 != 0 && key == "UNKNOWN" {
+		return e.KeyValues[length-1], true
+	}
+	return nil, false
+}
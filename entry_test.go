@@ -0,0 +1,30 @@
+package logx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntry_Value(t *testing.T) {
+	a := assert.New(t)
+	e := Entry{KeyValues: []interface{}{"k1", 1, 2, "v2", "v3"}}
+
+	v, ok := e.Value("k1")
+	a.True(ok)
+	a.Equal(1, v)
+
+	v, ok = e.Value("2")
+	a.True(ok)
+	a.Equal("v2", v)
+
+	v, ok = e.Value("UNKNOWN")
+	a.True(ok)
+	a.Equal("v3", v)
+
+	_, ok = e.Value("v2")
+	a.False(ok)
+
+	_, ok = Entry{KeyValues: []interface{}{"k1", 1}}.Value("UNKNOWN")
+	a.False(ok)
+}
@@ -1,9 +1,15 @@
 package logx
 
+import (
+	"reflect"
+	"regexp"
+)
+
 // FilterLevel wraps the given Logger, returns a new Logger which can filter log messages by the Level mask.
 // If the level of a log message is included in the mask, it is logged; otherwise it is dropped.
 //
 // e.g. If levelMast is Warn|Error|Fatal , only messages with level greater than Warn are logged.
+// LevelAtLeast() can be used to build the mask, including custom levels.
 //
 func FilterLevel(raw Logger, levelMask Level) Logger {
 	return logLevelFilter{raw, levelMask}
@@ -28,3 +34,185 @@ func (f logLevelFilter) LogFn(level Level, messageFactory func() (message string
 	}
 	return f.logger.LogFn(level, messageFactory)
 }
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (f logLevelFilter) Unwrap() Logger {
+	return f.logger
+}
+
+// Enabled implements LevelEnabler.Enabled().
+func (f logLevelFilter) Enabled(level Level) bool {
+	return (f.levelMask&level) == level && Enabled(f.logger, level)
+}
+
+// Predicate decides whether a log message should be logged, it is used by FilterBy.
+// All methods should be safe for concurrent use.
+type Predicate interface {
+	// Match returns true if the log message should be logged.
+	// If NeedsMessage returns false, only Entry.Level is set.
+	Match(entry Entry) bool
+
+	// NeedsMessage reports whether Match needs the message and key-values of the log message.
+	// If it returns false, LogFn factories are not invoked for dropped log messages.
+	NeedsMessage() bool
+}
+
+// Filter wraps the given Logger, returns a new Logger which logs only the log messages matched by pred.
+// The LogFn factory is always invoked, since pred may need the message; use FilterBy with a Predicate
+// whose NeedsMessage returns false to avoid that.
+func Filter(raw Logger, pred func(Entry) bool) Logger {
+	return FilterBy(raw, PredicateFunc(pred))
+}
+
+// FilterBy wraps the given Logger, returns a new Logger which logs only the log messages matched by the Predicate.
+func FilterBy(raw Logger, pred Predicate) Logger {
+	return predicateFilter{raw, pred}
+}
+
+// predicateFilter is a Logger which filters log messages with a Predicate.
+type predicateFilter struct {
+	logger Logger
+	pred   Predicate
+}
+
+func (f predicateFilter) Log(level Level, message string, keyValues ...interface{}) error {
+	if !f.pred.Match(Entry{Level: level, Message: message, KeyValues: keyValues}) {
+		return nil
+	}
+	return f.logger.Log(level, message, keyValues...)
+}
+
+func (f predicateFilter) LogFn(level Level, messageFactory func() (message string, keyValues []interface{})) error {
+	if !f.pred.NeedsMessage() {
+		if !f.pred.Match(Entry{Level: level}) {
+			return nil
+		}
+		return f.logger.LogFn(level, messageFactory)
+	}
+
+	if !Enabled(f.logger, level) {
+		return nil
+	}
+
+	message, keyValues := messageFactory()
+	return f.Log(level, message, keyValues...)
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (f predicateFilter) Unwrap() Logger {
+	return f.logger
+}
+
+// Enabled implements LevelEnabler.Enabled().
+// If the Predicate needs the message, it cannot be decided by the level, the wrapped Logger decides.
+func (f predicateFilter) Enabled(level Level) bool {
+	if !f.pred.NeedsMessage() && !f.pred.Match(Entry{Level: level}) {
+		return false
+	}
+	return Enabled(f.logger, level)
+}
+
+// PredicateFunc returns a Predicate with the given function, which needs the message.
+func PredicateFunc(fn func(Entry) bool) Predicate {
+	return predicate{fn, true}
+}
+
+// LevelPredicate returns a Predicate which matches log messages whose level is included in the mask.
+// It does not need the message.
+func LevelPredicate(levelMask Level) Predicate {
+	return predicate{func(e Entry) bool { return (levelMask & e.Level) == e.Level }, false}
+}
+
+// IncludeMessage returns a Predicate which matches log messages whose message matches the regular expression.
+func IncludeMessage(re *regexp.Regexp) Predicate {
+	return predicate{func(e Entry) bool { return re.MatchString(e.Message) }, true}
+}
+
+// ExcludeMessage returns a Predicate which matches log messages whose message does not match the regular expression.
+func ExcludeMessage(re *regexp.Regexp) Predicate {
+	return predicate{func(e Entry) bool { return !re.MatchString(e.Message) }, true}
+}
+
+// HasKey returns a Predicate which matches log messages whose key-values contain the given key.
+// Keys are compared with their string representation given by fmt.Sprint().
+func HasKey(key string) Predicate {
+	return predicate{func(e Entry) bool {
+		_, ok := e.Value(key)
+		return ok
+	}, true}
+}
+
+// LacksKey returns a Predicate which matches log messages whose key-values do not contain the given key.
+// Keys are compared with their string representation given by fmt.Sprint().
+func LacksKey(key string) Predicate {
+	return predicate{func(e Entry) bool {
+		_, ok := e.Value(key)
+		return !ok
+	}, true}
+}
+
+// KeyEquals returns a Predicate which matches log messages whose key-values contain the given key,
+// and the value of the key equals to the given value. Values are compared with reflect.DeepEqual().
+// Keys are compared with their string representation given by fmt.Sprint().
+func KeyEquals(key string, value interface{}) Predicate {
+	return predicate{func(e Entry) bool {
+		v, ok := e.Value(key)
+		return ok && reflect.DeepEqual(v, value)
+	}, true}
+}
+
+// And returns a Predicate which matches log messages matched by all of the given predicates.
+// It needs the message if any of the predicates needs it.
+// If no predicate is given, it matches all log messages.
+func And(preds ...Predicate) Predicate {
+	return predicate{func(e Entry) bool {
+		for _, p := range preds {
+			if !p.Match(e) {
+				return false
+			}
+		}
+		return true
+	}, anyNeedsMessage(preds)}
+}
+
+// Or returns a Predicate which matches log messages matched by any of the given predicates.
+// It needs the message if any of the predicates needs it.
+// If no predicate is given, it matches no log message.
+func Or(preds ...Predicate) Predicate {
+	return predicate{func(e Entry) bool {
+		for _, p := range preds {
+			if p.Match(e) {
+				return true
+			}
+		}
+		return false
+	}, anyNeedsMessage(preds)}
+}
+
+// Not returns a Predicate which matches log messages not matched by the given predicate.
+func Not(pred Predicate) Predicate {
+	return predicate{func(e Entry) bool { return !pred.Match(e) }, pred.NeedsMessage()}
+}
+
+// predicate is the implementation of Predicate used by the functions in this file.
+type predicate struct {
+	match        func(Entry) bool
+	needsMessage bool
+}
+
+func (p predicate) Match(entry Entry) bool {
+	return p.match(entry)
+}
+
+func (p predicate) NeedsMessage() bool {
+	return p.needsMessage
+}
+
+func anyNeedsMessage(preds []Predicate) bool {
+	for _, p := range preds {
+		if p.NeedsMessage() {
+			return true
+		}
+	}
+	return false
+}
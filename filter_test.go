@@ -1,6 +1,7 @@
 package logx_test
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/cmstar/go-logx"
@@ -31,3 +32,69 @@ func TestFilterLevel(t *testing.T) {
 	assert.Equal(t, "d2", r.Messages[2].Message)
 	assert.Equal(t, logx.LevelDebug, r.Messages[2].Level)
 }
+
+func TestFilter(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.Filter(r, func(e logx.Entry) bool {
+		return e.Level == logx.LevelError || e.Message == "x"
+	})
+
+	l.Log(logx.LevelDebug, "d")
+	l.Log(logx.LevelDebug, "x")
+	l.Log(logx.LevelError, "e")
+	l.LogFn(logx.LevelInfo, func() (string, []interface{}) { return "x", []interface{}{"k", 1} })
+
+	assert.Equal(t, "DEBUG x\nERROR e\nINFO x k=1\n", r.String())
+}
+
+func TestFilterBy(t *testing.T) {
+	tests := []struct {
+		name string
+		pred logx.Predicate
+		want string
+	}{
+		{"LevelPredicate", logx.LevelPredicate(logx.LevelWarn), "WARN b k2=2\n"},
+		{"IncludeMessage", logx.IncludeMessage(regexp.MustCompile("^a")), "DEBUG a1 k1=1\nERROR a2\n"},
+		{"ExcludeMessage", logx.ExcludeMessage(regexp.MustCompile("^a")), "WARN b k2=2\nINFO c UNKNOWN=3\n"},
+		{"HasKey", logx.HasKey("k2"), "WARN b k2=2\n"},
+		{"HasKey-unknown", logx.HasKey("UNKNOWN"), "INFO c UNKNOWN=3\n"},
+		{"LacksKey", logx.LacksKey("k1"), "WARN b k2=2\nERROR a2\nINFO c UNKNOWN=3\n"},
+		{"KeyEquals", logx.KeyEquals("k1", 1), "DEBUG a1 k1=1\n"},
+		{"KeyEquals-type", logx.KeyEquals("k1", "1"), ""},
+		{"And", logx.And(logx.IncludeMessage(regexp.MustCompile("^a")), logx.LevelPredicate(logx.LevelError)), "ERROR a2\n"},
+		{"And-empty", logx.And(), "DEBUG a1 k1=1\nWARN b k2=2\nERROR a2\nINFO c UNKNOWN=3\n"},
+		{"Or", logx.Or(logx.HasKey("k1"), logx.LevelPredicate(logx.LevelInfo)), "DEBUG a1 k1=1\nINFO c UNKNOWN=3\n"},
+		{"Or-empty", logx.Or(), ""},
+		{"Not", logx.Not(logx.LevelPredicate(logx.LevelDebug | logx.LevelInfo)), "WARN b k2=2\nERROR a2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := logxtest.NewRecorder()
+			l := logx.FilterBy(r, tt.pred)
+
+			l.Log(logx.LevelDebug, "a1", "k1", 1)
+			l.Log(logx.LevelWarn, "b", "k2", 2)
+			l.LogFn(logx.LevelError, func() (string, []interface{}) { return "a2", nil })
+			l.Log(logx.LevelInfo, "c", 3)
+
+			assert.Equal(t, tt.want, r.String())
+		})
+	}
+}
+
+func TestFilterBy_noMessage(t *testing.T) {
+	r := logxtest.NewRecorder()
+	pred := logx.Or(logx.LevelPredicate(logx.LevelInfo), logx.Not(logx.LevelPredicate(logx.LevelBeyondInfo)))
+	l := logx.FilterBy(r, pred)
+
+	assert.False(t, pred.NeedsMessage())
+	l.LogFn(logx.LevelWarn, func() (string, []interface{}) {
+		t.Fatal("the factory should not be invoked")
+		return "", nil
+	})
+	l.LogFn(logx.LevelInfo, func() (string, []interface{}) { return "i", nil })
+	l.LogFn(logx.LevelDebug, func() (string, []interface{}) { return "d", nil })
+
+	assert.Equal(t, "INFO i\nDEBUG d\n", r.String())
+}
@@ -0,0 +1,35 @@
+package logx
+
+// Flusher can be implemented by Loggers which buffer log messages, such as the Logger created by Dedup.
+type Flusher interface {
+	// Flush writes all buffered log messages.
+	Flush() error
+}
+
+// Unwrapper can be implemented by Loggers which wrap another Logger, such as the Loggers created by
+// FilterLevel, RateLimit and Dedup. It makes the chain of Loggers walkable, see Flush().
+type Unwrapper interface {
+	// Unwrap returns the wrapped Logger.
+	Unwrap() Logger
+}
+
+// Flush walks the chain of Loggers starting from the given one, following Unwrapper.Unwrap(),
+// and calls Flush() on each Logger implementing Flusher, from the outermost to the innermost.
+// All Flushers are called even if some of them fail, the first error is returned.
+func Flush(logger Logger) error {
+	var firstErr error
+	for logger != nil {
+		if f, ok := logger.(Flusher); ok {
+			if err := f.Flush(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		u, ok := logger.(Unwrapper)
+		if !ok {
+			break
+		}
+		logger = u.Unwrap()
+	}
+	return firstErr
+}
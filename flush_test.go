@@ -0,0 +1,63 @@
+package logx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// flushRecorder is a Logger which records the calls of Flush().
+type flushRecorder struct {
+	logx.Logger
+	name    string
+	err     error
+	flushed *[]string
+}
+
+func (f flushRecorder) Flush() error {
+	*f.flushed = append(*f.flushed, f.name)
+	return f.err
+}
+
+func (f flushRecorder) Unwrap() logx.Logger {
+	return f.Logger
+}
+
+func TestFlush(t *testing.T) {
+	var flushed []string
+	inner := flushRecorder{logxtest.NewRecorder(), "inner", errors.New("inner"), &flushed}
+	middle := flushRecorder{logx.FilterLevel(inner, logx.LevelBeyondInfo), "middle", errors.New("middle"), &flushed}
+	outer := logx.Op(logx.RateLimit(middle, logx.RateLimitConfig{}))
+
+	a := assert.New(t)
+	a.EqualError(logx.Flush(outer), "middle")
+	a.Equal([]string{"middle", "inner"}, flushed)
+
+	a.NoError(logx.Flush(nil))
+	a.NoError(logx.Flush(logx.NopLogger))
+}
+
+func TestFlush_dedup(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.Redact(logx.Dedup(r, logx.DedupConfig{}), logx.RedactConfig{})
+	l.Log(logx.LevelInfo, "a")
+	l.Log(logx.LevelInfo, "a")
+
+	assert.NoError(t, logx.Flush(l))
+	assert.Equal(t, "INFO a\nINFO a repeated=1\n", r.String())
+}
+
+func TestFlush_handler(t *testing.T) {
+	r := logxtest.NewRecorder()
+	d := logx.Dedup(r, logx.DedupConfig{})
+	l := logx.NewHandlerLogger("", logx.LoggerHandler(d))
+	l.Log(logx.LevelInfo, "a")
+	l.Log(logx.LevelInfo, "a")
+
+	assert.NoError(t, logx.Flush(l))
+	assert.Equal(t, "INFO a\nINFO a repeated=1\n", r.String())
+	assert.NoError(t, logx.NewHandlerLogger("", r).Flush())
+}
@@ -0,0 +1,196 @@
+package logx
+
+import (
+	"context"
+	"time"
+)
+
+// Handler processes log entries, it is the building block of a logging pipeline.
+// All methods should be safe for concurrent use.
+//
+// A pipeline is a chain of Middlewares ending with a sink Handler, built by Chain(),
+// and adapted to a Logger with NewHandlerLogger(). Existing Loggers can be used as sinks with LoggerHandler().
+type Handler interface {
+	// Handle processes the given Entry, returns an error if the processing failed.
+	Handle(entry Entry) error
+}
+
+// HandlerFunc is an adapter to allow the use of ordinary functions as Handlers.
+type HandlerFunc func(entry Entry) error
+
+// Handle calls f(entry).
+func (f HandlerFunc) Handle(entry Entry) error {
+	return f(entry)
+}
+
+// Middleware wraps a Handler, returns a new Handler which does something before or after calling the next one.
+// Typical middlewares are filters, which drop entries, and enrichers, which modify entries.
+type Middleware func(next Handler) Handler
+
+// Chain returns a Handler which sends entries through the middlewares in order, then to the sink.
+// Chain(sink, m1, m2, m3) is equivalent to m1(m2(m3(sink))), m1 is the first to receive entries.
+//
+// The recommended order is: filters first, so that dropped entries are not processed further;
+// then enrichers; then the sink. e.g.
+//
+//	h := logx.Chain(logx.LoggerHandler(logger),
+//	    logx.FilterMiddleware(logx.LevelPredicate(logx.LevelBeyondInfo)), // Filters.
+//	    logx.EnrichMiddleware(func(e *logx.Entry) { ... }),                // Enrichers.
+//	)
+func Chain(sink Handler, middlewares ...Middleware) Handler {
+	h := sink
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// FilterMiddleware returns a Middleware which sends only the entries matched by the Predicate to the next Handler.
+func FilterMiddleware(pred Predicate) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(entry Entry) error {
+			if !pred.Match(entry) {
+				return nil
+			}
+			return next.Handle(entry)
+		})
+	}
+}
+
+// EnrichMiddleware returns a Middleware which modifies entries with the given function,
+// then sends them to the next Handler.
+// The KeyValues of the entry can be shared with the caller of the logger, it should be replaced
+// by a new slice rather than being modified in place.
+func EnrichMiddleware(enrich func(entry *Entry)) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(entry Entry) error {
+			enrich(&entry)
+			return next.Handle(entry)
+		})
+	}
+}
+
+// LoggerHandler returns a Handler which sends the level, message and key-values of entries to the given Logger.
+// Other fields of the entries are dropped.
+// The Handler implements LevelEnabler, which calls Enabled() with the Logger;
+// and Flusher, which calls Flush() with the Logger.
+func LoggerHandler(logger Logger) Handler {
+	return loggerHandler{logger}
+}
+
+// loggerHandler is the Handler returned by LoggerHandler().
+type loggerHandler struct {
+	logger Logger
+}
+
+func (h loggerHandler) Handle(entry Entry) error {
+	return h.logger.Log(entry.Level, entry.Message, entry.KeyValues...)
+}
+
+func (h loggerHandler) Enabled(level Level) bool {
+	return Enabled(h.logger, level)
+}
+
+func (h loggerHandler) Flush() error {
+	return Flush(h.logger)
+}
+
+// HandlerLogger is a Logger which creates an Entry for each log message, and sends it to the Handler.
+type HandlerLogger struct {
+	// Handler receives the entries.
+	Handler Handler
+
+	// Name is set to Entry.Name.
+	Name string
+
+	// Caller, if true, makes Entry.Caller be set to the caller of the logging function.
+	// Frames of the Go runtime and this package are skipped. Capturing the caller is expensive.
+	Caller bool
+
+	// Now returns the time set to Entry.Time. If it is nil, time.Now is used.
+	Now func() time.Time
+
+	// Context is set to Entry.Context.
+	Context context.Context
+}
+
+var _ Logger = (*HandlerLogger)(nil)
+
+// NewHandlerLogger creates a new HandlerLogger with the given name and Handler.
+func NewHandlerLogger(name string, handler Handler) *HandlerLogger {
+	return &HandlerLogger{
+		Handler: handler,
+		Name:    name,
+	}
+}
+
+// Log implements Logger.Log().
+func (l *HandlerLogger) Log(level Level, message string, keyValues ...interface{}) error {
+	entry := Entry{
+		Level:     level,
+		Message:   message,
+		KeyValues: keyValues,
+		Name:      l.Name,
+		Context:   l.Context,
+	}
+
+	if l.Now == nil {
+		entry.Time = time.Now()
+	} else {
+		entry.Time = l.Now()
+	}
+
+	if l.Caller {
+		entry.Caller = callerFrame()
+	}
+
+	return l.Handler.Handle(entry)
+}
+
+// LogFn implements Logger.LogFn().
+// The messageFactory is not invoked if the level is disabled, see Enabled().
+func (l *HandlerLogger) LogFn(level Level, messageFactory func() (string, []interface{})) error {
+	if !l.Enabled(level) {
+		return nil
+	}
+
+	message, keyValues := messageFactory()
+	return l.Log(level, message, keyValues...)
+}
+
+// Enabled implements LevelEnabler.Enabled().
+// If the Handler implements LevelEnabler, returns the result of its Enabled(); otherwise returns true.
+func (l *HandlerLogger) Enabled(level Level) bool {
+	if e, ok := l.Handler.(LevelEnabler); ok {
+		return e.Enabled(level)
+	}
+	return true
+}
+
+// Flush implements Flusher.Flush().
+// If the Handler implements Flusher, calls its Flush(); otherwise does nothing.
+func (l *HandlerLogger) Flush() error {
+	if f, ok := l.Handler.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// WithContext returns a copy of the HandlerLogger, whose Context field is set to ctx.
+func (l *HandlerLogger) WithContext(ctx context.Context) Logger {
+	cp := *l
+	cp.Context = ctx
+	return &cp
+}
+
+// WithContext returns a Logger which binds the given context to its log messages, if the given Logger
+// supports that by having a method WithContext(context.Context) Logger , such as HandlerLogger;
+// otherwise the given Logger is returned.
+func WithContext(logger Logger, ctx context.Context) Logger {
+	if l, ok := logger.(interface {
+		WithContext(context.Context) Logger
+	}); ok {
+		return l.WithContext(ctx)
+	}
+	return logger
+}
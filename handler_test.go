@@ -0,0 +1,91 @@
+package logx_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	var trace []string
+	m := func(name string) logx.Middleware {
+		return func(next logx.Handler) logx.Handler {
+			return logx.HandlerFunc(func(entry logx.Entry) error {
+				trace = append(trace, name)
+				return next.Handle(entry)
+			})
+		}
+	}
+
+	sink := logx.HandlerFunc(func(entry logx.Entry) error {
+		trace = append(trace, "sink")
+		return errors.New("err")
+	})
+
+	h := logx.Chain(sink, m("m1"), m("m2"), m("m3"))
+	err := h.Handle(logx.Entry{})
+
+	assert.EqualError(t, err, "err")
+	assert.Equal(t, []string{"m1", "m2", "m3", "sink"}, trace)
+}
+
+func TestHandlerLogger(t *testing.T) {
+	type ctxKey struct{}
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	var entries []logx.Entry
+
+	l := logx.NewHandlerLogger("a.b", logx.HandlerFunc(func(entry logx.Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	l.Caller = true
+	l.Now = func() time.Time { return now }
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	logx.Op(logx.WithContext(l, ctx)).Infokv("k", 1)
+	l.LogFn(logx.LevelDebug, func() (string, []interface{}) { return "d", nil })
+
+	a := assert.New(t)
+	a.Equal(2, len(entries))
+
+	e := entries[0]
+	a.Equal(now, e.Time)
+	a.Equal(logx.LevelInfo, e.Level)
+	a.Equal("", e.Message)
+	a.Equal([]interface{}{"k", 1}, e.KeyValues)
+	a.Equal("a.b", e.Name)
+	a.Equal("v", e.Context.Value(ctxKey{}))
+	a.True(strings.HasSuffix(e.Caller.Function, ".TestHandlerLogger"), e.Caller.Function)
+	a.True(strings.HasSuffix(e.Caller.File, "handler_test.go"), e.Caller.File)
+
+	e = entries[1]
+	a.Equal("d", e.Message)
+	a.Nil(e.Context)
+}
+
+func TestHandlerLogger_pipeline(t *testing.T) {
+	r := logxtest.NewRecorder()
+	h := logx.Chain(logx.LoggerHandler(r),
+		logx.FilterMiddleware(logx.LevelPredicate(logx.LevelBeyondInfo)),
+		logx.EnrichMiddleware(func(e *logx.Entry) {
+			e.KeyValues = append([]interface{}{"logger", e.Name}, e.KeyValues...)
+		}),
+	)
+	l := logx.NewHandlerLogger("x", h)
+
+	l.Log(logx.LevelDebug, "d")
+	l.Log(logx.LevelInfo, "i", "k", 1)
+
+	assert.Equal(t, "INFO i logger=x k=1\n", r.String())
+}
+
+func TestWithContext(t *testing.T) {
+	r := logxtest.NewRecorder()
+	assert.Same(t, r, logx.WithContext(r, context.Background()))
+}
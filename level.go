@@ -0,0 +1,338 @@
+package logx
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// The orders of the built-in levels, see LevelOrder().
+const (
+	LevelOrderTrace = 100
+	LevelOrderDebug = 200
+	LevelOrderInfo  = 300
+	LevelOrderWarn  = 400
+	LevelOrderError = 500
+	LevelOrderFatal = 600
+)
+
+// levelDef is a registered level.
+type levelDef struct {
+	name  string // The name in uppercase.
+	level Level  // A single bit.
+	order int
+}
+
+var (
+	// levelDefs stores a []levelDef sorted by order, which is replaced on each registration,
+	// thus it can be read without locks.
+	levelDefs atomic.Value
+
+	// levelDefsMu is the lock for registrations.
+	levelDefsMu sync.Mutex
+)
+
+func init() {
+	levelDefs.Store([]levelDef{
+		{"TRACE", LevelTrace, LevelOrderTrace},
+		{"DEBUG", LevelDebug, LevelOrderDebug},
+		{"INFO", LevelInfo, LevelOrderInfo},
+		{"WARN", LevelWarn, LevelOrderWarn},
+		{"ERROR", LevelError, LevelOrderError},
+		{"FATAL", LevelFatal, LevelOrderFatal},
+	})
+}
+
+func loadLevelDefs() []levelDef {
+	return levelDefs.Load().([]levelDef)
+}
+
+// RegisterLevel registers a custom level, such as AUDIT or NOTICE, with its name, bit and order.
+// Registered levels are supported by ParseLevelMask(), LevelToString(), LevelOrder() and LevelAtLeast(),
+// and can be used in level masks like the built-in ones.
+//
+// The name is case-insensitive, it must not be empty or contain spaces and the characters used by level
+// expressions. The level must be a single bit not used by other levels, that is, LevelCustom1 or LevelCustom2.
+// The order decides where the level is among others, see the LevelOrderXxx constants for
+// the orders of the built-in levels, e.g. a NOTICE level between INFO and WARN can have the order 350.
+//
+// It is usually called in init functions, before any logging.
+func RegisterLevel(name string, level Level, order int) error {
+	name = strings.ToUpper(name)
+	if name == "" || strings.ContainsAny(name, " \t|,<>=.+") {
+		return fmt.Errorf("logx: invalid level name %q", name)
+	}
+
+	if level == 0 || level&(level-1) != 0 {
+		return fmt.Errorf("logx: level %s must be a single bit, got %d", name, level)
+	}
+
+	levelDefsMu.Lock()
+	defer levelDefsMu.Unlock()
+
+	defs := loadLevelDefs()
+	for _, d := range defs {
+		if d.name == name {
+			return fmt.Errorf("logx: level name %s is already registered", name)
+		}
+		if d.level == level {
+			return fmt.Errorf("logx: level bit %d is already registered by %s", level, d.name)
+		}
+	}
+
+	newDefs := make([]levelDef, len(defs), len(defs)+1)
+	copy(newDefs, defs)
+	newDefs = append(newDefs, levelDef{name, level, order})
+	sort.SliceStable(newDefs, func(i, j int) bool { return newDefs[i].order < newDefs[j].order })
+
+	levelDefs.Store(newDefs)
+	return nil
+}
+
+// errLevelNotFound is returned by findLevelDef when the level cannot be found.
+var errLevelNotFound = errors.New("logx: level not found")
+
+// findLevelDef returns the registered level with the given name, which is case-insensitive.
+func findLevelDef(name string) (levelDef, error) {
+	name = strings.ToUpper(name)
+	for _, d := range loadLevelDefs() {
+		if d.name == name {
+			return d, nil
+		}
+	}
+	return levelDef{}, errLevelNotFound
+}
+
+// LevelOrder returns the order of the given level, which decides whether a level is higher than another.
+// If the given level is a combined level, returns the highest order of the levels.
+// If the level is not registered, returns -1.
+func LevelOrder(level Level) int {
+	order := -1
+	for _, d := range loadLevelDefs() {
+		if level&d.level != 0 {
+			order = d.order
+		}
+	}
+	return order
+}
+
+// LevelAtLeast returns a mask combining all registered levels whose order is not less than the order of
+// the given level. e.g. LevelAtLeast(LevelWarn) returns LevelBeyondWarn, plus the registered custom levels
+// whose orders are greater than LevelOrderWarn.
+// If the level is not registered, returns 0.
+//
+// The result can be used by FilterLevel(); since the mask is calculated when this function is called,
+// custom levels should be registered before that.
+func LevelAtLeast(level Level) Level {
+	order := LevelOrder(level)
+	if order < 0 {
+		return 0
+	}
+
+	var mask Level
+	for _, d := range loadLevelDefs() {
+		if d.order >= order {
+			mask |= d.level
+		}
+	}
+	return mask
+}
+
+var (
+	_ encoding.TextMarshaler   = Level(0)
+	_ encoding.TextUnmarshaler = (*Level)(nil)
+	_ json.Unmarshaler         = (*Level)(nil)
+	_ flag.Value               = (*Level)(nil)
+)
+
+// ParseLevelMask parses a level expression to a Level, which can be a combined level.
+// Level names are case-insensitive, and can be the names of custom levels registered by RegisterLevel().
+//
+// An expression is one or more terms separated by '|' or ',', the result combines the levels of all terms.
+// A term can be:
+//   - A level name, e.g. INFO .
+//   - ALL, all registered levels; NONE, no level.
+//   - A threshold: '>=WARN' or 'WARN+' for levels not lower than WARN; '>WARN', '<=WARN' and '<WARN' are similar.
+//   - A range: 'INFO..ERROR' for levels from INFO to ERROR, inclusive.
+//
+// Levels are compared by LevelOrder(). e.g. 'DEBUG|>=ERROR' is equivalent to 'DEBUG|ERROR|FATAL'.
+//
+// It round-trips with LevelToString(), that is, ParseLevelMask(LevelToString(v)) returns v if v combines
+// only registered levels.
+func ParseLevelMask(expr string) (Level, error) {
+	terms := strings.FieldsFunc(expr, func(r rune) bool { return r == '|' || r == ',' })
+	if len(terms) == 0 {
+		return 0, fmt.Errorf("logx: empty level expression %q", expr)
+	}
+
+	var mask Level
+	for _, term := range terms {
+		lv, err := parseLevelTerm(strings.TrimSpace(term))
+		if err != nil {
+			return 0, err
+		}
+		mask |= lv
+	}
+	return mask, nil
+}
+
+// parseLevelTerm parses a term of a level expression, see ParseLevelMask().
+func parseLevelTerm(term string) (Level, error) {
+	upper := strings.ToUpper(term)
+	switch upper {
+	case "":
+		return 0, errors.New("logx: empty term in level expression")
+	case "ALL":
+		return knownLevels(), nil
+	case "NONE":
+		return 0, nil
+	}
+
+	if i := strings.Index(upper, ".."); i >= 0 {
+		from, err := levelOrderOf(upper[:i])
+		if err != nil {
+			return 0, err
+		}
+
+		to, err := levelOrderOf(upper[i+2:])
+		if err != nil {
+			return 0, err
+		}
+
+		if from > to {
+			return 0, fmt.Errorf("logx: invalid level range %q", term)
+		}
+		return levelsInOrder(func(o int) bool { return o >= from && o <= to }), nil
+	}
+
+	thresholds := []struct {
+		prefix, suffix string
+		match          func(order, threshold int) bool
+	}{
+		{">=", "", func(o, t int) bool { return o >= t }},
+		{"<=", "", func(o, t int) bool { return o <= t }},
+		{">", "", func(o, t int) bool { return o > t }},
+		{"<", "", func(o, t int) bool { return o < t }},
+		{"", "+", func(o, t int) bool { return o >= t }},
+	}
+	for _, th := range thresholds {
+		if th.prefix != "" && !strings.HasPrefix(upper, th.prefix) ||
+			th.suffix != "" && !strings.HasSuffix(upper, th.suffix) {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(upper, th.prefix), th.suffix)
+		threshold, err := levelOrderOf(name)
+		if err != nil {
+			return 0, err
+		}
+
+		match := th.match
+		return levelsInOrder(func(o int) bool { return match(o, threshold) }), nil
+	}
+
+	d, err := findLevelDef(upper)
+	if err != nil {
+		return 0, fmt.Errorf("logx: unknown level %q", term)
+	}
+	return d.level, nil
+}
+
+// levelOrderOf returns the order of the level with the given name.
+func levelOrderOf(name string) (int, error) {
+	name = strings.TrimSpace(name)
+	d, err := findLevelDef(name)
+	if err != nil {
+		return 0, fmt.Errorf("logx: unknown level %q", name)
+	}
+	return d.order, nil
+}
+
+// levelsInOrder returns a mask combining all registered levels whose order matches the function.
+func levelsInOrder(match func(order int) bool) Level {
+	var mask Level
+	for _, d := range loadLevelDefs() {
+		if match(d.order) {
+			mask |= d.level
+		}
+	}
+	return mask
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// It returns the result of LevelToString(), or NONE if v is 0.
+// Returns an error if v contains bits of unregistered levels.
+func (v Level) MarshalText() ([]byte, error) {
+	if v == 0 {
+		return []byte("NONE"), nil
+	}
+
+	if v&^knownLevels() != 0 {
+		return nil, fmt.Errorf("logx: unknown level bits in %d", v)
+	}
+	return []byte(LevelToString(v)), nil
+}
+
+// knownLevels returns a mask combining all registered levels.
+func knownLevels() Level {
+	return levelsInOrder(func(int) bool { return true })
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, the text is parsed by ParseLevelMask().
+// A decimal number is also accepted as the numeric value of the Level, it is the format used
+// before Level implements encoding.TextMarshaler, e.g. '6' is LevelInfo|LevelWarn; the number must
+// only contain the bits of registered levels.
+func (v *Level) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if n, err := strconv.ParseInt(s, 10, 8); err == nil {
+		if Level(n)&^knownLevels() != 0 {
+			return fmt.Errorf("logx: unknown level bits in %d", n)
+		}
+
+		*v = Level(n)
+		return nil
+	}
+
+	lv, err := ParseLevelMask(s)
+	if err != nil {
+		return err
+	}
+
+	*v = lv
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a JSON string, which is parsed by UnmarshalText(),
+// or a JSON number, which is the format before Level implements encoding.TextMarshaler. null is a no-op.
+func (v *Level) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '"' {
+		if string(data) == "null" {
+			return nil
+		}
+		return v.UnmarshalText(data)
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return v.UnmarshalText([]byte(s))
+}
+
+// Set implements flag.Value, the value is parsed by UnmarshalText().
+// e.g. a Level can be used as a command-line flag:
+//
+//	level := logx.LevelBeyondInfo
+//	flag.Var(&level, "level", "log levels")
+func (v *Level) Set(value string) error {
+	return v.UnmarshalText([]byte(value))
+}
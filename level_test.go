@@ -0,0 +1,218 @@
+package logx
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withCustomLevels registers custom levels for a test, and restores the registry after the test.
+func withCustomLevels(t *testing.T) (notice, audit Level) {
+	defs := loadLevelDefs()
+	t.Cleanup(func() { levelDefs.Store(defs) })
+
+	notice, audit = LevelCustom1, LevelCustom2
+	assert.NoError(t, RegisterLevel("notice", notice, 350))
+	assert.NoError(t, RegisterLevel("AUDIT", audit, 1000))
+	return
+}
+
+func TestRegisterLevel(t *testing.T) {
+	notice, audit := withCustomLevels(t)
+
+	a := assert.New(t)
+	a.Equal(notice, ParseLevel("Notice"))
+	a.Equal(audit, ParseLevel("audit"))
+	a.Equal("NOTICE", notice.String())
+	a.Equal("INFO|NOTICE|WARN|AUDIT", LevelToString(LevelInfo|LevelWarn|notice|audit))
+
+	a.EqualError(RegisterLevel("", LevelCustom1, 0), `logx: invalid level name ""`)
+	a.EqualError(RegisterLevel("A B", LevelCustom1, 0), `logx: invalid level name "A B"`)
+	a.EqualError(RegisterLevel("A|B", LevelCustom1, 0), `logx: invalid level name "A|B"`)
+	a.EqualError(RegisterLevel("x", 0, 0), "logx: level X must be a single bit, got 0")
+	a.EqualError(RegisterLevel("x", 3<<5, 0), "logx: level X must be a single bit, got 96")
+	a.EqualError(RegisterLevel("x", -1, 0), "logx: level X must be a single bit, got -1")
+	a.EqualError(RegisterLevel("info", LevelCustom1, 0), "logx: level name INFO is already registered")
+	a.EqualError(RegisterLevel("x", LevelTrace, 0), "logx: level bit 32 is already registered by TRACE")
+	a.EqualError(RegisterLevel("x", LevelCustom2, 0), "logx: level bit -128 is already registered by AUDIT")
+}
+
+func TestLevelOrder(t *testing.T) {
+	a := assert.New(t)
+	a.Equal(-1, LevelOrder(LevelCustom1))
+
+	notice, _ := withCustomLevels(t)
+	a.Equal(LevelOrderTrace, LevelOrder(LevelTrace))
+	a.Equal(LevelOrderDebug, LevelOrder(LevelDebug))
+	a.Equal(LevelOrderInfo, LevelOrder(LevelInfo))
+	a.Equal(LevelOrderWarn, LevelOrder(LevelWarn))
+	a.Equal(LevelOrderError, LevelOrder(LevelError))
+	a.Equal(LevelOrderFatal, LevelOrder(LevelFatal))
+	a.Equal(350, LevelOrder(notice))
+	a.Equal(LevelOrderError, LevelOrder(LevelDebug|LevelError))
+	a.Equal(-1, LevelOrder(0))
+}
+
+func TestLevelAtLeast(t *testing.T) {
+	a := assert.New(t)
+	a.Equal(LevelBeyondTrace, LevelAtLeast(LevelTrace))
+	a.Equal(LevelBeyondDebug, LevelAtLeast(LevelDebug))
+	a.Equal(LevelBeyondWarn, LevelAtLeast(LevelWarn))
+	a.Equal(LevelFatal, LevelAtLeast(LevelFatal))
+	a.Equal(Level(0), LevelAtLeast(LevelCustom1))
+
+	notice, audit := withCustomLevels(t)
+	a.Equal(LevelBeyondWarn|notice|audit, LevelAtLeast(notice))
+	a.Equal(LevelBeyondInfo|notice|audit, LevelAtLeast(LevelInfo))
+	a.Equal(audit, LevelAtLeast(audit))
+}
+
+func TestParseLevelMask(t *testing.T) {
+	tests := []struct {
+		expr string
+		want Level
+	}{
+		{"info", LevelInfo},
+		{" Debug | error ", LevelDebug | LevelError},
+		{"debug,error,fatal", LevelDebug | LevelError | LevelFatal},
+		{"ALL", LevelBeyondTrace},
+		{"none", 0},
+		{"none|warn", LevelWarn},
+		{">=WARN", LevelBeyondWarn},
+		{"warn+", LevelBeyondWarn},
+		{">warn", LevelBeyondError},
+		{"<=debug", LevelTrace | LevelDebug},
+		{"<debug", LevelTrace},
+		{"info..error", LevelInfo | LevelWarn | LevelError},
+		{"info..info", LevelInfo},
+		{"trace|>=error", LevelTrace | LevelBeyondError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseLevelMask(tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseLevelMask_error(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"", `logx: empty level expression ""`},
+		{" | ", "logx: empty term in level expression"},
+		{"x", `logx: unknown level "x"`},
+		{"info|x", `logx: unknown level "x"`},
+		{">=x", `logx: unknown level "X"`},
+		{"x+", `logx: unknown level "X"`},
+		{"x..info", `logx: unknown level "X"`},
+		{"info..x", `logx: unknown level "X"`},
+		{"error..info", `logx: invalid level range "error..info"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseLevelMask(tt.expr)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseLevelMask_roundTrip(t *testing.T) {
+	notice, audit := withCustomLevels(t)
+
+	levels := []Level{
+		LevelTrace, LevelDebug, LevelError, LevelBeyondTrace, LevelBeyondWarn,
+		LevelDebug | LevelFatal, notice, audit | LevelInfo | notice,
+	}
+	for _, lv := range levels {
+		got, err := ParseLevelMask(LevelToString(lv))
+		assert.NoError(t, err)
+		assert.Equal(t, lv, got)
+	}
+
+	got, err := ParseLevelMask(">=notice")
+	assert.NoError(t, err)
+	assert.Equal(t, LevelBeyondWarn|notice|audit, got)
+}
+
+func TestLevel_MarshalText(t *testing.T) {
+	a := assert.New(t)
+
+	b, err := LevelBeyondError.MarshalText()
+	a.NoError(err)
+	a.Equal("ERROR|FATAL", string(b))
+
+	b, err = Level(0).MarshalText()
+	a.NoError(err)
+	a.Equal("NONE", string(b))
+
+	_, err = (LevelInfo | LevelCustom1).MarshalText()
+	a.EqualError(err, "logx: unknown level bits in 66")
+
+	var lv Level
+	a.NoError(lv.UnmarshalText([]byte("warn+")))
+	a.Equal(LevelBeyondWarn, lv)
+
+	a.NoError(lv.UnmarshalText([]byte("NONE")))
+	a.Equal(Level(0), lv)
+
+	a.NoError(lv.UnmarshalText([]byte("6")))
+	a.Equal(LevelInfo|LevelWarn, lv)
+
+	a.Error(lv.UnmarshalText([]byte("x")))
+	a.EqualError(lv.UnmarshalText([]byte("-1")), "logx: unknown level bits in -1")
+	a.EqualError(lv.UnmarshalText([]byte("70")), "logx: unknown level bits in 70")
+	a.Error(lv.UnmarshalText([]byte("300")))
+	a.Equal(LevelInfo|LevelWarn, lv)
+
+	withCustomLevels(t)
+	a.NoError(lv.UnmarshalText([]byte("70")))
+	a.Equal(LevelInfo|LevelWarn|LevelCustom1, lv)
+}
+
+func TestLevel_flag(t *testing.T) {
+	level := LevelInfo
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&level, "level", "")
+
+	a := assert.New(t)
+	a.NoError(fs.Parse([]string{"-level", "debug|>=error"}))
+	a.Equal(LevelDebug|LevelBeyondError, level)
+	a.Equal("DEBUG|ERROR|FATAL", fs.Lookup("level").Value.String())
+
+	a.Error(fs.Parse([]string{"-level", "x"}))
+}
+
+func TestLevel_json(t *testing.T) {
+	type config struct {
+		Level Level
+	}
+
+	var c config
+	a := assert.New(t)
+	a.NoError(json.Unmarshal([]byte(`{"Level":"info..error"}`), &c))
+	a.Equal(LevelInfo|LevelWarn|LevelError, c.Level)
+
+	b, err := json.Marshal(c)
+	a.NoError(err)
+	a.Equal(`{"Level":"INFO|WARN|ERROR"}`, string(b))
+
+	// Numbers written before Level is marshaled as text.
+	a.NoError(json.Unmarshal([]byte(`{"Level":12}`), &c))
+	a.Equal(LevelWarn|LevelError, c.Level)
+	a.NoError(json.Unmarshal([]byte(`{"Level":"12"}`), &c))
+	a.Equal(LevelWarn|LevelError, c.Level)
+
+	a.NoError(json.Unmarshal([]byte(`{"Level":null}`), &c))
+	a.Equal(LevelWarn|LevelError, c.Level)
+	a.Error(json.Unmarshal([]byte(`{"Level":true}`), &c))
+	a.Error(json.Unmarshal([]byte(`{"Level":1.5}`), &c))
+}
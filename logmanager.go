@@ -86,6 +86,13 @@ func (m *LogManager) Op(name string) *LoggerOp {
 	return Op(l)
 }
 
+// Enabled uses Find() to get the logger with the specific name, and reports whether the given level
+// is enabled on it, see the function Enabled().
+// If the name cannot be found, returns false.
+func (m *LogManager) Enabled(name string, level Level) bool {
+	return Enabled(m.Find(name), level)
+}
+
 // Set registers a named logger to the current LogManager.
 // If a logger with the name already exists, it will be replaced.
 func (m *LogManager) Set(name string, logger Logger) {
@@ -1,14 +1,76 @@
 package logx
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
 
 // LoggerOp wraps a Logger interface, provides a group of shortcut operations
 // to call the methods of the Logger.
+//
+// A LoggerOp created by Op() or a literal such as LoggerOp{logger} uses the default settings,
+// use OpConfig.Op() to customize them.
 type LoggerOp struct {
 	Logger
 }
 
-// Op returns a LoggerOp which wraps the given Logger.
+// OpConfig configures the LoggerOp created by OpConfig.Op().
+// The settings are usually shared by the program, e.g.
+//
+//	var ops = logx.OpConfig{OnError: logx.ReportErrorToStderr}
+//	op := ops.Op(logger)
+type OpConfig struct {
+	// Exit is called by FatalExit() and its variants to terminate the program with the exit code 1.
+	// If it is nil, os.Exit is used. It can be stubbed for testing, see logxtest.StubExit().
+	Exit func(code int)
+
+	// OnError receives the errors returned by Logger.Log(), wrapped by *LogError.
+	// If it is nil, the errors are ignored. See ReportErrorTo(), ReportErrorToWriter() and ErrorCounter.
+	OnError func(err error)
+
+	// Now returns the current time for Time() and Scope(). If it is nil, time.Now is used.
+	// It is useful for testing.
+	Now func() time.Time
+
+	// SlowThreshold, if positive, makes Time() log at LevelWarn instead of LevelInfo,
+	// when the elapsed time exceeds it.
+	SlowThreshold time.Duration
+}
+
+// Op returns a LoggerOp which wraps the given Logger and uses the settings of the config.
+// If the given logger is nil, the LoggerOp will use a NopLogger.
+//
+// The settings are carried by the Logger of the LoggerOp, which wraps the given Logger,
+// LoggerOp.Unwrap() returns the given Logger.
+func (c OpConfig) Op(logger Logger) *LoggerOp {
+	if logger == nil {
+		logger = NopLogger
+	}
+	return &LoggerOp{&configuredLogger{logger, c}}
+}
+
+// defaultOpConfig is used by LoggerOp not created by OpConfig.Op().
+var defaultOpConfig OpConfig
+
+// configuredLogger carries the OpConfig of a LoggerOp, so that LoggerOp keeps a single field.
+type configuredLogger struct {
+	Logger
+	config OpConfig
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (l *configuredLogger) Unwrap() Logger {
+	return l.Logger
+}
+
+// Enabled implements LevelEnabler.Enabled(), the wrapped Logger decides.
+func (l *configuredLogger) Enabled(level Level) bool {
+	return Enabled(l.Logger, level)
+}
+
+// Op returns a LoggerOp which wraps the given Logger, with the default settings, see OpConfig.
 // If the given logger is nil, the LoggerOp will use a NopLogger.
 func Op(logger Logger) *LoggerOp {
 	if logger == nil {
@@ -17,82 +79,253 @@ func Op(logger Logger) *LoggerOp {
 	return &LoggerOp{logger}
 }
 
+// config returns the settings of the LoggerOp.
+func (op *LoggerOp) config() *OpConfig {
+	if l, ok := op.Logger.(*configuredLogger); ok {
+		return &l.config
+	}
+	return &defaultOpConfig
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (op *LoggerOp) Unwrap() Logger {
+	if l, ok := op.Logger.(*configuredLogger); ok {
+		return l.Logger
+	}
+	return op.Logger
+}
+
+// log calls Logger.Log(), the error returned is sent to OnError.
+func (op *LoggerOp) log(level Level, msg string, keyValues ...interface{}) {
+	err := op.Log(level, msg, keyValues...)
+	if onError := op.config().OnError; err != nil && onError != nil {
+		onError(&LogError{Level: level, Message: msg, Err: err})
+	}
+}
+
+// Enabled reports whether the given level is enabled on the Logger, see the function Enabled().
+// It can be used to avoid building expensive log messages.
+func (op *LoggerOp) Enabled(level Level) bool {
+	return Enabled(op.Logger, level)
+}
+
+// Logl calls Logger.Log() using the given level, without the key-value part.
+// It is useful for custom levels registered by RegisterLevel().
+func (op *LoggerOp) Logl(level Level, msg string) {
+	op.log(level, msg)
+}
+
+// Loglf calls Logger.Log() using the given level, without the key-value part,
+// and formats the message with fmt.Sprintf() if the level is enabled.
+func (op *LoggerOp) Loglf(level Level, format string, args ...interface{}) {
+	if !op.Enabled(level) {
+		return
+	}
+	op.log(level, fmt.Sprintf(format, args...))
+}
+
+// Loglkv calls Logger.Log() using the given level, without the message part.
+func (op *LoggerOp) Loglkv(level Level, keyValues ...interface{}) {
+	op.log(level, "", keyValues...)
+}
+
+// Trace calls Logger.Log() using LevelTrace, without the key-value part.
+func (op *LoggerOp) Trace(msg string) {
+	op.log(LevelTrace, msg)
+}
+
+// Tracef calls Logger.Log() using LevelTrace, without the key-value part,
+// and formats the message with fmt.Sprintf() if the level is enabled.
+func (op *LoggerOp) Tracef(format string, args ...interface{}) {
+	if !op.Enabled(LevelTrace) {
+		return
+	}
+	op.log(LevelTrace, fmt.Sprintf(format, args...))
+}
+
+// Tracekv calls Logger.Log() using LevelTrace, without the message part.
+func (op *LoggerOp) Tracekv(keyValues ...interface{}) {
+	op.log(LevelTrace, "", keyValues...)
+}
+
 // Debug calls Logger.Log() using LevelDebug, without the key-value part.
 func (op *LoggerOp) Debug(msg string) {
-	op.Log(LevelDebug, msg)
+	op.log(LevelDebug, msg)
 }
 
 // Debugf calls Logger.Log() using LevelDebug, without the key-value part,
-// and formats the message with fmt.Sprintf().
+// and formats the message with fmt.Sprintf() if the level is enabled.
 func (op *LoggerOp) Debugf(format string, args ...interface{}) {
-	op.Log(LevelDebug, fmt.Sprintf(format, args...))
+	if !op.Enabled(LevelDebug) {
+		return
+	}
+	op.log(LevelDebug, fmt.Sprintf(format, args...))
 }
 
 // Debugkv calls Logger.Log() using LevelDebug, without the message part.
 func (op *LoggerOp) Debugkv(keyValues ...interface{}) {
-	op.Log(LevelDebug, "", keyValues...)
+	op.log(LevelDebug, "", keyValues...)
 }
 
 // Info calls Logger.Log() using LevelInfo, without the key-value part.
 func (op *LoggerOp) Info(msg string) {
-	op.Log(LevelInfo, msg)
+	op.log(LevelInfo, msg)
 }
 
 // Infof calls Logger.Log() using LevelInfo, without the key-value part,
-// and formats the message with fmt.Sprintf().
+// and formats the message with fmt.Sprintf() if the level is enabled.
 func (op *LoggerOp) Infof(format string, args ...interface{}) {
-	op.Log(LevelInfo, fmt.Sprintf(format, args...))
+	if !op.Enabled(LevelInfo) {
+		return
+	}
+	op.log(LevelInfo, fmt.Sprintf(format, args...))
 }
 
 // Infokv calls Logger.Log() using LevelInfo, without the message part.
 func (op *LoggerOp) Infokv(keyValues ...interface{}) {
-	op.Log(LevelInfo, "", keyValues...)
+	op.log(LevelInfo, "", keyValues...)
 }
 
 // Warn calls Logger.Log() using LevelWarn, without the key-value part.
 func (op *LoggerOp) Warn(msg string) {
-	op.Log(LevelWarn, msg)
+	op.log(LevelWarn, msg)
 }
 
 // Warnf calls Logger.Log() using LevelWarn, without the key-value part,
-// and formats the message with fmt.Sprintf().
+// and formats the message with fmt.Sprintf() if the level is enabled.
 func (op *LoggerOp) Warnf(format string, args ...interface{}) {
-	op.Log(LevelWarn, fmt.Sprintf(format, args...))
+	if !op.Enabled(LevelWarn) {
+		return
+	}
+	op.log(LevelWarn, fmt.Sprintf(format, args...))
 }
 
 // Warnkv calls Logger.Log() using LevelWarn, without the message part.
 func (op *LoggerOp) Warnkv(keyValues ...interface{}) {
-	op.Log(LevelWarn, "", keyValues...)
+	op.log(LevelWarn, "", keyValues...)
 }
 
 // Error calls Logger.Log() using LevelError, without the key-value part.
 func (op *LoggerOp) Error(msg string) {
-	op.Log(LevelError, msg)
+	op.log(LevelError, msg)
 }
 
 // Errorf calls Logger.Log() using LevelError, without the key-value part,
-// and formats the message with fmt.Sprintf().
+// and formats the message with fmt.Sprintf() if the level is enabled.
 func (op *LoggerOp) Errorf(format string, args ...interface{}) {
-	op.Log(LevelError, fmt.Sprintf(format, args...))
+	if !op.Enabled(LevelError) {
+		return
+	}
+	op.log(LevelError, fmt.Sprintf(format, args...))
 }
 
 // Errorkv calls Logger.Log() using LevelError, without the message part.
 func (op *LoggerOp) Errorkv(keyValues ...interface{}) {
-	op.Log(LevelError, "", keyValues...)
+	op.log(LevelError, "", keyValues...)
 }
 
 // Fatal calls Logger.Log() using LevelFatal, without the key-value part.
+// It does not terminate the program, see FatalExit() and Panic().
 func (op *LoggerOp) Fatal(msg string) {
-	op.Log(LevelFatal, msg)
+	op.log(LevelFatal, msg)
 }
 
 // Fatalf calls Logger.Log() using LevelFatal, without the key-value part,
-// and formats the message with fmt.Sprintf().
+// and formats the message with fmt.Sprintf() if the level is enabled.
 func (op *LoggerOp) Fatalf(format string, args ...interface{}) {
-	op.Log(LevelFatal, fmt.Sprintf(format, args...))
+	if !op.Enabled(LevelFatal) {
+		return
+	}
+	op.log(LevelFatal, fmt.Sprintf(format, args...))
 }
 
 // Fatalkv calls Logger.Log() using LevelFatal, without the message part.
 func (op *LoggerOp) Fatalkv(keyValues ...interface{}) {
-	op.Log(LevelFatal, "", keyValues...)
+	op.log(LevelFatal, "", keyValues...)
+}
+
+// FatalExit calls Logger.Log() using LevelFatal, without the key-value part,
+// then flushes the Loggers in the chain with Flush(), and terminates the program with the exit code 1.
+func (op *LoggerOp) FatalExit(msg string) {
+	op.log(LevelFatal, msg)
+	op.exit()
+}
+
+// FatalExitf calls Logger.Log() using LevelFatal, without the key-value part,
+// and formats the message with fmt.Sprintf() if the level is enabled;
+// then flushes the Loggers in the chain with Flush(), and terminates the program with the exit code 1.
+func (op *LoggerOp) FatalExitf(format string, args ...interface{}) {
+	op.Fatalf(format, args...)
+	op.exit()
+}
+
+// FatalExitkv calls Logger.Log() using LevelFatal, without the message part,
+// then flushes the Loggers in the chain with Flush(), and terminates the program with the exit code 1.
+func (op *LoggerOp) FatalExitkv(keyValues ...interface{}) {
+	op.log(LevelFatal, "", keyValues...)
+	op.exit()
+}
+
+// exit flushes the Loggers and terminates the program.
+func (op *LoggerOp) exit() {
+	Flush(op.Logger)
+
+	exit := op.config().Exit
+	if exit == nil {
+		exit = os.Exit
+	}
+	exit(1)
+}
+
+// Panic calls Logger.Log() using LevelFatal, without the key-value part, then panics with the message.
+func (op *LoggerOp) Panic(msg string) {
+	op.log(LevelFatal, msg)
+	panic(msg)
+}
+
+// Panicf calls Logger.Log() using LevelFatal, without the key-value part,
+// then panics with the message formatted by fmt.Sprintf().
+func (op *LoggerOp) Panicf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	op.log(LevelFatal, msg)
+	panic(msg)
+}
+
+// Panickv calls Logger.Log() using LevelFatal, without the message part,
+// then panics with the key-values formatted as 'K1=V1 K2=V2'.
+func (op *LoggerOp) Panickv(keyValues ...interface{}) {
+	op.log(LevelFatal, "", keyValues...)
+	panic(formatKeyValues(keyValues))
+}
+
+// formatKeyValues formats the key-values as 'K1=V1 K2=V2', in the same manner of StdLogger.
+func formatKeyValues(keyValues []interface{}) string {
+	b := new(strings.Builder)
+	length := len(keyValues)
+	for i := 0; i < length-1; i += 2 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(b, "%v=%v", keyValues[i], keyValues[i+1])
+	}
+
+	if length%2 != 0 {
+		if length > 1 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(b, "UNKNOWN=%v", keyValues[length-1])
+	}
+	return b.String()
+}
+
+// appendPaired appends the key-values to dst, the unpaired last element gets the key 'UNKNOWN',
+// so that the key-values appended after them stay aligned.
+func appendPaired(dst []interface{}, keyValues []interface{}) []interface{} {
+	length := len(keyValues)
+	if length%2 == 0 {
+		return append(dst, keyValues...)
+	}
+
+	dst = append(dst, keyValues[:length-1]...)
+	return append(dst, "UNKNOWN", keyValues[length-1])
 }
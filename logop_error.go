@@ -0,0 +1,181 @@
+package logx
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// Keys of the key-value pairs added by LoggerOp.ErrorE() and its variants.
+const (
+	// ErrorKey is the key of the error.
+	ErrorKey = "error"
+
+	// ErrorCausesKey is the key of the messages of the errors wrapped by the error, which are
+	// found with 'Unwrap() error' and 'Unwrap() []error', depth-first.
+	ErrorCausesKey = "error_causes"
+)
+
+// LogFielder can be implemented by errors to provide key-value pairs, which are added to the log messages
+// by LoggerOp.ErrorE() and its variants.
+type LogFielder interface {
+	// LogFields returns the key-value pairs, e.g. []interface{}{"k1", "v1", "k2", "v2"}.
+	LogFields() []interface{}
+}
+
+// LogError is the error sent to OpConfig.OnError, it describes a log message which failed to be written.
+type LogError struct {
+	Level   Level  // The level of the log message.
+	Message string // The message of the log message.
+	Err     error  // The error returned by Logger.Log().
+}
+
+// Error implements error.Error().
+func (e *LogError) Error() string {
+	return fmt.Sprintf("logx: failed to write %s log %q: %v", LevelToString(e.Level), e.Message, e.Err)
+}
+
+// Unwrap returns the error returned by Logger.Log().
+func (e *LogError) Unwrap() error {
+	return e.Err
+}
+
+// IgnoreErrors can be used as OpConfig.OnError to ignore the errors explicitly.
+func IgnoreErrors(err error) {}
+
+// ReportErrorTo returns a function for OpConfig.OnError, which logs the errors to the fallback Logger
+// at LevelError, under the key ErrorKey.
+func ReportErrorTo(fallback Logger) func(err error) {
+	return func(err error) {
+		fallback.Log(LevelError, "failed to write log", ErrorKey, err)
+	}
+}
+
+// ReportErrorToWriter returns a function for OpConfig.OnError, which writes the errors to w, one error per line.
+// e.g. ReportErrorToWriter(os.Stderr).
+func ReportErrorToWriter(w io.Writer) func(err error) {
+	var mu sync.Mutex
+	return func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintln(w, err)
+	}
+}
+
+// ReportErrorToStderr can be used as OpConfig.OnError, it writes the errors to os.Stderr, one error per line.
+// It is ReportErrorToWriter(os.Stderr), the lines written concurrently are not interleaved.
+var ReportErrorToStderr = ReportErrorToWriter(os.Stderr)
+
+// ErrorCounter counts the errors, its method OnError can be used as OpConfig.OnError.
+// It is safe for concurrent use.
+type ErrorCounter struct {
+	count int64
+
+	mu   sync.Mutex
+	last error
+}
+
+// OnError counts the error.
+func (c *ErrorCounter) OnError(err error) {
+	atomic.AddInt64(&c.count, 1)
+
+	c.mu.Lock()
+	c.last = err
+	c.mu.Unlock()
+}
+
+// Count returns the number of errors counted.
+func (c *ErrorCounter) Count() int64 {
+	return atomic.LoadInt64(&c.count)
+}
+
+// Last returns the last error counted, returns nil if there is none.
+func (c *ErrorCounter) Last() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// LoglE calls Logger.Log() using the given level, with the error and the key-values.
+// The key-values are followed by:
+//   - ErrorKey and the error;
+//   - ErrorCausesKey and the messages of the errors wrapped by the error, if any;
+//   - the key-values provided by the error and the wrapped errors implementing LogFielder, from the outermost.
+//
+// If the error is nil, the message is logged with the key-values only.
+// Nothing is done if the level is disabled.
+func (op *LoggerOp) LoglE(level Level, err error, msg string, keyValues ...interface{}) {
+	if !op.Enabled(level) {
+		return
+	}
+	op.log(level, msg, errorKeyValues(err, keyValues)...)
+}
+
+// WarnE calls Logger.Log() using LevelWarn, with the error and the key-values, see LoglE().
+func (op *LoggerOp) WarnE(err error, msg string, keyValues ...interface{}) {
+	op.LoglE(LevelWarn, err, msg, keyValues...)
+}
+
+// ErrorE calls Logger.Log() using LevelError, with the error and the key-values, see LoglE().
+func (op *LoggerOp) ErrorE(err error, msg string, keyValues ...interface{}) {
+	op.LoglE(LevelError, err, msg, keyValues...)
+}
+
+// FatalE calls Logger.Log() using LevelFatal, with the error and the key-values, see LoglE().
+// It does not terminate the program.
+func (op *LoggerOp) FatalE(err error, msg string, keyValues ...interface{}) {
+	op.LoglE(LevelFatal, err, msg, keyValues...)
+}
+
+// errorKeyValues returns the key-values followed by the ones extracted from the error.
+// The unpaired last element of the key-values gets the key 'UNKNOWN', so that the appended keys stay aligned.
+func errorKeyValues(err error, keyValues []interface{}) []interface{} {
+	if err == nil {
+		return keyValues
+	}
+
+	res := appendPaired(make([]interface{}, 0, len(keyValues)+5), keyValues)
+	res = append(res, ErrorKey, err)
+
+	var causes []string
+	var fields []interface{}
+	outermost := true
+	walkErrors(err, func(e error) {
+		if outermost {
+			outermost = false
+		} else {
+			causes = append(causes, e.Error())
+		}
+		if f, ok := e.(LogFielder); ok {
+			fields = append(fields, f.LogFields()...)
+		}
+	})
+
+	if len(causes) > 0 {
+		res = append(res, ErrorCausesKey, causes)
+	}
+	return append(res, fields...)
+}
+
+// walkErrors calls fn with the error and the errors wrapped by it, depth-first from the outermost.
+// Both 'Unwrap() error' and 'Unwrap() []error', which is implemented by the errors joined by errors.Join()
+// since Go 1.20, are followed.
+func walkErrors(err error, fn func(error)) {
+	for err != nil {
+		fn(err)
+
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				walkErrors(e, fn)
+			}
+			return
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package logx_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingLogger is a Logger which always fails. If the recorder is not nil, the messages are recorded before failing.
+type failingLogger struct {
+	recorder *logxtest.LogRecorder
+}
+
+func (l failingLogger) Log(level logx.Level, message string, keyValues ...interface{}) error {
+	if l.recorder != nil {
+		l.recorder.Log(level, message, keyValues...)
+	}
+	return errors.New("broken")
+}
+
+func (l failingLogger) LogFn(level logx.Level, messageFactory func() (string, []interface{})) error {
+	message, keyValues := messageFactory()
+	return l.Log(level, message, keyValues...)
+}
+
+func TestLoggerOp_OnError(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("ignore", func(t *testing.T) {
+		r := logxtest.NewRecorder()
+		logx.Op(failingLogger{r}).Info("i")
+		logx.OpConfig{OnError: logx.IgnoreErrors}.Op(failingLogger{r}).Info("i")
+
+		// Only the messages themselves, no error reports.
+		a.Equal("INFO i\nINFO i\n", r.String())
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		r := logxtest.NewRecorder()
+		op := logx.OpConfig{OnError: logx.ReportErrorTo(r)}.Op(failingLogger{})
+		op.Warnf("w%v", 1)
+		a.Equal(`ERROR failed to write log error=logx: failed to write WARN log "w1": broken`+"\n", r.String())
+	})
+
+	t.Run("writer", func(t *testing.T) {
+		buf := new(strings.Builder)
+		op := logx.OpConfig{OnError: logx.ReportErrorToWriter(buf)}.Op(failingLogger{})
+		op.Errorkv("k", "v")
+		a.Equal(`logx: failed to write ERROR log "": broken`+"\n", buf.String())
+	})
+
+	t.Run("counter", func(t *testing.T) {
+		c := new(logx.ErrorCounter)
+		a.Nil(c.Last())
+
+		op := logx.OpConfig{OnError: c.OnError}.Op(failingLogger{})
+		op.Info("a")
+		op.ErrorE(errors.New("e"), "b")
+
+		a.Equal(int64(2), c.Count())
+		var logErr *logx.LogError
+		a.True(errors.As(c.Last(), &logErr))
+		a.Equal(logx.LevelError, logErr.Level)
+		a.Equal("b", logErr.Message)
+		a.EqualError(errors.Unwrap(logErr), "broken")
+	})
+
+	t.Run("noError", func(t *testing.T) {
+		c := new(logx.ErrorCounter)
+		op := logx.OpConfig{OnError: c.OnError}.Op(logxtest.NewRecorder())
+		op.Info("a")
+		a.Equal(int64(0), c.Count())
+	})
+}
+
+// fieldsError is an error implementing LogFielder.
+type fieldsError struct {
+	msg    string
+	err    error
+	fields []interface{}
+}
+
+func (e fieldsError) Error() string {
+	if e.err == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e fieldsError) Unwrap() error            { return e.err }
+func (e fieldsError) LogFields() []interface{} { return e.fields }
+
+func TestLoggerOp_ErrorE(t *testing.T) {
+	r := logxtest.NewRecorder()
+	op := logx.Op(r)
+
+	root := fieldsError{"root", nil, []interface{}{"code", 404}}
+	mid := fmt.Errorf("mid: %w", root)
+	top := fieldsError{"top", mid, []interface{}{"user", "u1"}}
+
+	op.ErrorE(top, "e", "k", 1)
+	op.WarnE(errors.New("plain"), "w")
+	op.FatalE(nil, "f", "k", 2)
+	op.LoglE(logx.LevelInfo, mid, "i")
+	op.WarnE(errors.New("odd"), "o", "k", 1, 2)
+
+	assert.Equal(t, `ERROR e k=1 error=top: mid: root error_causes=[mid: root root] user=u1 code=404
+WARN w error=plain
+FATAL f k=2
+INFO i error=mid: root error_causes=[root] code=404
+WARN o k=1 UNKNOWN=2 error=odd
+`, r.String())
+}
+
+// joinedError wraps several errors, like the errors joined by errors.Join() since Go 1.20.
+type joinedError []error
+
+func (e joinedError) Error() string   { return "joined" }
+func (e joinedError) Unwrap() []error { return e }
+
+func TestLoggerOp_ErrorE_joined(t *testing.T) {
+	r := logxtest.NewRecorder()
+	op := logx.Op(r)
+
+	a := fieldsError{"a", nil, []interface{}{"code", 1}}
+	b := fmt.Errorf("b: %w", fieldsError{"c", nil, []interface{}{"code", 2}})
+	op.ErrorE(fmt.Errorf("top: %w", joinedError{a, b}), "e")
+
+	assert.Equal(t, "ERROR e error=top: joined error_causes=[joined a b: c c] code=1 code=2\n", r.String())
+}
+
+func TestLoggerOp_ErrorE_disabled(t *testing.T) {
+	r := logxtest.NewRecorder()
+	op := logx.Op(logx.FilterLevel(r, logx.LevelFatal))
+
+	op.ErrorE(errors.New("e"), "e")
+	op.FatalE(errors.New("f"), "f")
+	assert.Equal(t, "FATAL f error=f\n", r.String())
+}
@@ -51,7 +51,99 @@ FATAL  k1=51 k2=52
 	assert.Equal(t, want, r.String())
 }
 
+func TestLoggerOp_trace(t *testing.T) {
+	r := logxtest.NewRecorder()
+	op := logx.Op(r)
+
+	op.Trace("Trace msg")
+	op.Tracef("Trace %v", 0)
+	op.Tracekv("k1", 1, "k2", 2)
+
+	op.Logl(logx.LevelWarn, "Logl msg")
+	op.Loglf(logx.LevelTrace, "Logl %v", 1)
+	op.Loglkv(logx.LevelError, "k1", 1)
+
+	want := `TRACE Trace msg
+TRACE Trace 0
+TRACE  k1=1 k2=2
+WARN Logl msg
+TRACE Logl 1
+ERROR  k1=1
+`
+	assert.Equal(t, want, r.String())
+}
+
 func TestLoggerOp_withNilLogger(t *testing.T) {
 	op := logx.Op(nil)
 	op.Debug("")
 }
+
+func TestLoggerOp_literal(t *testing.T) {
+	r := logxtest.NewRecorder()
+	op := logx.LoggerOp{r}
+	op.Info("i")
+
+	a := assert.New(t)
+	a.Equal("INFO i\n", r.String())
+	a.Same(r, op.Unwrap())
+}
+
+func TestOpConfig_Op(t *testing.T) {
+	r := logxtest.NewRecorder()
+	op := logx.OpConfig{}.Op(logx.FilterLevel(r, logx.LevelInfo))
+	op.Info("i")
+	op.Debug("d")
+
+	a := assert.New(t)
+	a.Equal("INFO i\n", r.String())
+	a.True(op.Enabled(logx.LevelInfo))
+	a.False(op.Enabled(logx.LevelDebug))
+	a.NotNil(op.Unwrap().(logx.Unwrapper).Unwrap())
+
+	logx.OpConfig{}.Op(nil).Debug("")
+}
+
+func TestLoggerOp_FatalExit(t *testing.T) {
+	r := logxtest.NewRecorder()
+	d := logx.Dedup(r, logx.DedupConfig{})
+	var config logx.OpConfig
+	exit := logxtest.StubExit(&config)
+	op := config.Op(d)
+
+	op.Info("a")
+	op.Info("a")
+	op.FatalExit("f1")
+	op.FatalExitf("f%v", 2)
+	op.FatalExitkv("k", 3)
+
+	a := assert.New(t)
+	a.Equal([]int{1, 1, 1}, exit.Codes())
+	a.Equal(`INFO a
+FATAL f1
+INFO a repeated=1
+FATAL f2
+FATAL  k=3
+`, r.String())
+
+	// Each LoggerOp uses the Exit of its own config.
+	own := new(logxtest.ExitRecorder)
+	logx.OpConfig{Exit: own.Exit}.Op(d).FatalExit("f4")
+	a.Equal([]int{1}, own.Codes())
+	a.Equal(3, len(exit.Codes()))
+}
+
+func TestLoggerOp_Panic(t *testing.T) {
+	r := logxtest.NewRecorder()
+	op := logx.Op(r)
+
+	a := assert.New(t)
+	a.PanicsWithValue("p1", func() { op.Panic("p1") })
+	a.PanicsWithValue("p2", func() { op.Panicf("p%v", 2) })
+	a.PanicsWithValue("k1=1 k2=2 UNKNOWN=3", func() { op.Panickv("k1", 1, "k2", 2, 3) })
+	a.PanicsWithValue("UNKNOWN=1", func() { op.Panickv(1) })
+	a.Equal(`FATAL p1
+FATAL p2
+FATAL  k1=1 k2=2 UNKNOWN=3
+FATAL  UNKNOWN=1
+`, r.String())
+}
@@ -0,0 +1,84 @@
+package logx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Time starts a timer, returns a function which stops the timer and logs the message name with the key-values,
+// followed by the key-value pair elapsed=DURATION. The message is logged at LevelInfo, or at LevelWarn if
+// OpConfig.SlowThreshold is positive and exceeded. e.g.
+//
+//	defer op.Time("query", "table", "users")()
+//
+// Use TimeThreshold() to give the operation its own threshold.
+func (op *LoggerOp) Time(name string, keyValues ...interface{}) func() {
+	return op.TimeThreshold(name, op.config().SlowThreshold, keyValues...)
+}
+
+// TimeThreshold is similar to Time(), but the message is logged at LevelWarn if the given threshold is positive
+// and exceeded, instead of OpConfig.SlowThreshold. e.g.
+//
+//	defer op.TimeThreshold("query", 100*time.Millisecond, "table", "users")()
+func (op *LoggerOp) TimeThreshold(name string, threshold time.Duration, keyValues ...interface{}) func() {
+	start := op.now()
+	return func() {
+		elapsed := op.now().Sub(start)
+
+		level := LevelInfo
+		if threshold > 0 && elapsed > threshold {
+			level = LevelWarn
+		}
+
+		kv := make([]interface{}, 0, len(keyValues)+2)
+		kv = append(kv, keyValues...)
+		kv = append(kv, "elapsed", elapsed)
+		op.log(level, name, kv...)
+	}
+}
+
+// Scope logs the beginning of an operation, returns a function which logs the end of it.
+// The returned function must be deferred, e.g.
+//
+//	defer op.Scope("sync")()
+//
+// Both messages are logged at LevelInfo with the message name and a key-value pair scope=ID,
+// where ID is generated randomly to correlate the messages. The beginning has phase=begin;
+// the end has phase=end and elapsed=DURATION.
+//
+// If the operation panics, the end is logged at LevelError with an extra key-value pair panic=VALUE,
+// then the panic continues.
+func (op *LoggerOp) Scope(name string) func() {
+	id := newScopeID()
+	start := op.now()
+	op.log(LevelInfo, name, "scope", id, "phase", "begin")
+
+	return func() {
+		elapsed := op.now().Sub(start)
+
+		if p := recover(); p != nil {
+			op.log(LevelError, name, "scope", id, "phase", "end", "elapsed", elapsed, "panic", p)
+			panic(p)
+		}
+		op.log(LevelInfo, name, "scope", id, "phase", "end", "elapsed", elapsed)
+	}
+}
+
+// now returns the current time with OpConfig.Now.
+func (op *LoggerOp) now() time.Time {
+	if now := op.config().Now; now != nil {
+		return now()
+	}
+	return time.Now()
+}
+
+// newScopeID returns a random ID of 16 hex digits.
+func newScopeID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
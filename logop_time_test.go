@@ -0,0 +1,73 @@
+package logx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock returns a function for OpConfig.Now, whose result moves forward by step on each call.
+func fakeClock(step time.Duration) func() time.Time {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+func TestLoggerOp_Time(t *testing.T) {
+	r := logxtest.NewRecorder()
+	logx.OpConfig{Now: fakeClock(time.Second)}.Op(r).Time("a", "k", 1)()
+
+	config := logx.OpConfig{Now: fakeClock(time.Second), SlowThreshold: 2 * time.Second}
+	config.Op(r).Time("b")()
+
+	config.Now = fakeClock(3 * time.Second)
+	config.Op(r).Time("c")()
+
+	// The threshold of the call overrides OpConfig.SlowThreshold.
+	config.Op(r).TimeThreshold("d", 5*time.Second, "k", 1)()
+	config.Op(r).TimeThreshold("e", time.Second)()
+	config.Op(r).TimeThreshold("f", 0)()
+
+	assert.Equal(t, `INFO a k=1 elapsed=1s
+INFO b elapsed=1s
+WARN c elapsed=3s
+INFO d k=1 elapsed=3s
+WARN e elapsed=3s
+INFO f elapsed=3s
+`, r.String())
+}
+
+func TestLoggerOp_Scope(t *testing.T) {
+	r := logxtest.NewRecorder()
+	op := logx.OpConfig{Now: fakeClock(time.Second)}.Op(r)
+
+	func() {
+		defer op.Scope("s1")()
+	}()
+
+	a := assert.New(t)
+	a.PanicsWithValue("oops", func() {
+		defer op.Scope("s2")()
+		panic("oops")
+	})
+
+	a.Equal(4, len(r.Messages))
+	id1 := r.Messages[0].KeyValues[1]
+	id2 := r.Messages[2].KeyValues[1]
+	a.Len(id1, 16)
+	a.NotEqual(id1, id2)
+
+	a.Equal(logxtest.LogMessage{Level: logx.LevelInfo, Message: "s1",
+		KeyValues: []interface{}{"scope", id1, "phase", "begin"}}, r.Messages[0])
+	a.Equal(logxtest.LogMessage{Level: logx.LevelInfo, Message: "s1",
+		KeyValues: []interface{}{"scope", id1, "phase", "end", "elapsed", time.Second}}, r.Messages[1])
+	a.Equal(logxtest.LogMessage{Level: logx.LevelInfo, Message: "s2",
+		KeyValues: []interface{}{"scope", id2, "phase", "begin"}}, r.Messages[2])
+	a.Equal(logxtest.LogMessage{Level: logx.LevelError, Message: "s2",
+		KeyValues: []interface{}{"scope", id2, "phase", "end", "elapsed", time.Second, "panic", "oops"}}, r.Messages[3])
+}
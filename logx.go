@@ -1,13 +1,13 @@
 // Package logx provides abstraction and some simple implementation of logging.
 package logx
 
-import (
-	"fmt"
-	"strings"
-)
+import "fmt"
 
-// Level defines the log level.
-// If level A is lower than B, the integer value of A should be less than B.
+// Level defines the log level. Each level is a bit, levels can be combined as a bitmap mask.
+//
+// Levels are ordered by LevelOrder(). For the built-in levels except LevelTrace, if level A is lower than B,
+// the integer value of A is less than B. Custom levels can be registered with RegisterLevel(),
+// using the bits LevelCustom1 and LevelCustom2.
 type Level int8
 
 // Levels can be a bitmap mask.
@@ -17,6 +17,17 @@ const (
 	LevelWarn                    // LevelWarn is the warn level.
 	LevelError                   // LevelError is the error level.
 	LevelFatal                   // LevelFatal is the fatal level.
+
+	// LevelTrace is the trace level, which is lower than LevelDebug.
+	// It takes the bit after LevelFatal to keep the values of other levels unchanged.
+	LevelTrace
+)
+
+// The bits not used by the built-in levels, they can be registered as custom levels by RegisterLevel().
+// LevelCustom2 is the sign bit, its value is negative.
+const (
+	LevelCustom1 Level = 1 << 6
+	LevelCustom2 Level = -1 << 7
 )
 
 var _ fmt.Stringer = Level(0)
@@ -40,60 +51,40 @@ const (
 
 	// LevelBeyondError combines LevelFatal, LevelError, LevelWarn, LevelInfo, LevelDebug.
 	LevelBeyondDebug = LevelDebug | LevelBeyondInfo
+
+	// LevelBeyondTrace combines LevelFatal, LevelError, LevelWarn, LevelInfo, LevelDebug, LevelTrace.
+	LevelBeyondTrace = LevelTrace | LevelBeyondDebug
 )
 
-// ParseLevel parses the given string to the corresponding Level.
+// ParseLevel parses the given string to the corresponding Level, the string is the case-insensitive name of
+// a built-in level or a level registered by RegisterLevel().
 // Returns -1 if the value cannot be parsed.
+//
+// Deprecated: -1 cannot be told apart from a mask of all bits, use ParseLevelMask(), which returns an error
+// if the value cannot be parsed, and also parses combined levels.
 func ParseLevel(v string) Level {
-	v = strings.ToUpper(v)
-	switch v {
-	case "DEBUG":
-		return LevelDebug
-	case "INFO":
-		return LevelInfo
-	case "WARN":
-		return LevelWarn
-	case "ERROR":
-		return LevelError
-	case "FATAL":
-		return LevelFatal
+	d, err := findLevelDef(v)
+	if err != nil {
+		return -1
 	}
-	return -1
+	return d.level
 }
 
 // LevelToString returns the string representation of Level.
 //
-// The string is in uppercase like DEBUG, INFO, WARN, ERROR, FATAL.
-// Combined levels are split by '|', e.g. DEBUG|INFO|ERROR .
+// The string is in uppercase like TRACE, DEBUG, INFO, WARN, ERROR, FATAL, or the name of a level registered
+// by RegisterLevel(). Combined levels are split by '|' and ordered by LevelOrder(), e.g. DEBUG|INFO|ERROR .
 //
 // If the given level is not defined, returns UNKNOWN.
 func LevelToString(lv Level) string {
 	res := ""
-	a := func(s string) {
-		if len(res) > 0 {
-			res += "|"
+	for _, d := range loadLevelDefs() {
+		if (lv & d.level) == d.level {
+			if len(res) > 0 {
+				res += "|"
+			}
+			res += d.name
 		}
-		res += s
-	}
-
-	if (lv & LevelDebug) == LevelDebug {
-		a("DEBUG")
-	}
-
-	if (lv & LevelInfo) == LevelInfo {
-		a("INFO")
-	}
-
-	if (lv & LevelWarn) == LevelWarn {
-		a("WARN")
-	}
-
-	if (lv & LevelError) == LevelError {
-		a("ERROR")
-	}
-
-	if (lv & LevelFatal) == LevelFatal {
-		a("FATAL")
 	}
 
 	if len(res) == 0 {
@@ -14,6 +14,7 @@ func TestLevel_String(t *testing.T) {
 	a.Equal("ERROR", LevelError.String())
 	a.Equal("FATAL", LevelFatal.String())
 	a.Equal("DEBUG|FATAL", (LevelDebug | LevelFatal).String())
+	a.Equal("TRACE", LevelTrace.String())
 }
 
 func TestParseLevel(t *testing.T) {
@@ -23,6 +24,7 @@ func TestParseLevel(t *testing.T) {
 	a.Equal(LevelWarn, ParseLevel("warn"))
 	a.Equal(LevelError, ParseLevel("Error"))
 	a.Equal(LevelFatal, ParseLevel("fatal"))
+	a.Equal(LevelTrace, ParseLevel("Trace"))
 	a.Equal(Level(-1), ParseLevel("x"))
 }
 
@@ -33,10 +35,14 @@ func TestLevelToString(t *testing.T) {
 	a.Equal("WARN", LevelToString(LevelWarn))
 	a.Equal("ERROR", LevelToString(LevelError))
 	a.Equal("FATAL", LevelToString(LevelFatal))
-	a.Equal("UNKNOWN", LevelToString(LevelFatal<<1))
+	a.Equal("TRACE", LevelToString(LevelTrace))
+	a.Equal("UNKNOWN", LevelToString(LevelCustom1))
+	a.Equal("UNKNOWN", LevelToString(0))
 
 	a.Equal("ERROR|FATAL", LevelToString(LevelBeyondError))
 	a.Equal("WARN|ERROR|FATAL", LevelToString(LevelBeyondWarn))
 	a.Equal("INFO|WARN|ERROR|FATAL", LevelToString(LevelBeyondInfo))
 	a.Equal("DEBUG|INFO|WARN|ERROR|FATAL", LevelToString(LevelBeyondDebug))
+	a.Equal("TRACE|DEBUG|INFO|WARN|ERROR|FATAL", LevelToString(LevelBeyondTrace))
+	a.Equal("TRACE|ERROR", LevelToString(LevelError|LevelTrace))
 }
@@ -0,0 +1,2 @@
+// Package logxgrpc integrates google.golang.org/grpc with the logx package.
+package logxgrpc
@@ -0,0 +1,112 @@
+package logxgrpc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmstar/go-logx"
+	"google.golang.org/grpc/grpclog"
+)
+
+// DefaultLoggerName is the name usually used to find the logger for gRPC internals.
+const DefaultLoggerName = "grpc"
+
+// loggerV2 is a grpclog.LoggerV2 which writes log messages to the logx.Logger found by a logx.LogFinder.
+type loggerV2 struct {
+	finder    logx.LogFinder
+	name      string
+	verbosity int
+}
+
+var _ grpclog.LoggerV2 = (*loggerV2)(nil)
+
+// NewLoggerV2 creates a grpclog.LoggerV2 which writes log messages to the logx.Logger found by the given
+// logx.LogFinder with the given name, such as DefaultLoggerName.
+// The logger is found on each log message, thus changes of the logx.LogFinder, such as logx.LogManager.Set(),
+// take effect immediately. If no logger can be found, log messages are dropped.
+//
+// V(l) returns true if l is not greater than the given verbosity.
+// Messages are formatted only if the level is enabled, see logx.Enabled().
+//
+// Fatal methods log at logx.LevelFatal and do not exit, grpclog exits the process after calling them.
+//
+// Use grpclog.SetLoggerV2() to install it, e.g.
+//
+//	grpclog.SetLoggerV2(logxgrpc.NewLoggerV2(logx.DefaultManager, logxgrpc.DefaultLoggerName, 0))
+func NewLoggerV2(finder logx.LogFinder, name string, verbosity int) grpclog.LoggerV2 {
+	return &loggerV2{finder, name, verbosity}
+}
+
+func (l *loggerV2) Info(args ...interface{}) {
+	l.print(logx.LevelInfo, args)
+}
+
+func (l *loggerV2) Infoln(args ...interface{}) {
+	l.println(logx.LevelInfo, args)
+}
+
+func (l *loggerV2) Infof(format string, args ...interface{}) {
+	l.printf(logx.LevelInfo, format, args)
+}
+
+func (l *loggerV2) Warning(args ...interface{}) {
+	l.print(logx.LevelWarn, args)
+}
+
+func (l *loggerV2) Warningln(args ...interface{}) {
+	l.println(logx.LevelWarn, args)
+}
+
+func (l *loggerV2) Warningf(format string, args ...interface{}) {
+	l.printf(logx.LevelWarn, format, args)
+}
+
+func (l *loggerV2) Error(args ...interface{}) {
+	l.print(logx.LevelError, args)
+}
+
+func (l *loggerV2) Errorln(args ...interface{}) {
+	l.println(logx.LevelError, args)
+}
+
+func (l *loggerV2) Errorf(format string, args ...interface{}) {
+	l.printf(logx.LevelError, format, args)
+}
+
+func (l *loggerV2) Fatal(args ...interface{}) {
+	l.print(logx.LevelFatal, args)
+}
+
+func (l *loggerV2) Fatalln(args ...interface{}) {
+	l.println(logx.LevelFatal, args)
+}
+
+func (l *loggerV2) Fatalf(format string, args ...interface{}) {
+	l.printf(logx.LevelFatal, format, args)
+}
+
+func (l *loggerV2) V(level int) bool {
+	return level <= l.verbosity
+}
+
+func (l *loggerV2) print(level logx.Level, args []interface{}) {
+	l.log(level, func() string { return fmt.Sprint(args...) })
+}
+
+func (l *loggerV2) println(level logx.Level, args []interface{}) {
+	l.log(level, func() string { return strings.TrimSuffix(fmt.Sprintln(args...), "\n") })
+}
+
+func (l *loggerV2) printf(level logx.Level, format string, args []interface{}) {
+	l.log(level, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// log finds the logger and logs the message, the message is formatted only if the logger exists.
+func (l *loggerV2) log(level logx.Level, format func() string) {
+	logger := l.finder.Find(l.name)
+	if !logx.Enabled(logger, level) {
+		return
+	}
+
+	logger.LogFn(level, func() (string, []interface{}) { return format(), nil })
+}
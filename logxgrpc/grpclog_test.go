@@ -0,0 +1,49 @@
+package logxgrpc
+
+import (
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerV2(t *testing.T) {
+	m := logx.NewManager()
+	l := NewLoggerV2(m, DefaultLoggerName, 2)
+	l.Info("dropped")
+
+	r := logxtest.NewRecorder()
+	m.Set("grpc", r)
+
+	l.Info("a", 1, 2, "b")
+	l.Infoln("a", 1, 2, "b")
+	l.Infof("i %d", 1)
+	l.Warning("w")
+	l.Warningln("w")
+	l.Warningf("w %d", 2)
+	l.Error("e")
+	l.Errorln("e")
+	l.Errorf("e %d", 3)
+	l.Fatal("f")
+	l.Fatalln("f")
+	l.Fatalf("f %d", 4)
+
+	assert.Equal(t, `INFO a1 2b
+INFO a 1 2 b
+INFO i 1
+WARN w
+WARN w
+WARN w 2
+ERROR e
+ERROR e
+ERROR e 3
+FATAL f
+FATAL f
+FATAL f 4
+`, r.String())
+
+	assert.True(t, l.V(0))
+	assert.True(t, l.V(2))
+	assert.False(t, l.V(3))
+}
@@ -0,0 +1,327 @@
+package logxgrpc
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Default messages of the log messages written by the interceptors.
+const (
+	DefaultServerMessage = "grpc server call"
+	DefaultClientMessage = "grpc client call"
+)
+
+// defaultCodeLevels is the table returned by DefaultCodeLevels().
+var defaultCodeLevels = map[codes.Code]logx.Level{
+	codes.OK:                 logx.LevelInfo,
+	codes.Canceled:           logx.LevelInfo,
+	codes.Unknown:            logx.LevelError,
+	codes.InvalidArgument:    logx.LevelInfo,
+	codes.DeadlineExceeded:   logx.LevelWarn,
+	codes.NotFound:           logx.LevelInfo,
+	codes.AlreadyExists:      logx.LevelInfo,
+	codes.PermissionDenied:   logx.LevelWarn,
+	codes.ResourceExhausted:  logx.LevelWarn,
+	codes.FailedPrecondition: logx.LevelWarn,
+	codes.Aborted:            logx.LevelWarn,
+	codes.OutOfRange:         logx.LevelWarn,
+	codes.Unimplemented:      logx.LevelError,
+	codes.Internal:           logx.LevelError,
+	codes.Unavailable:        logx.LevelWarn,
+	codes.DataLoss:           logx.LevelError,
+	codes.Unauthenticated:    logx.LevelInfo,
+}
+
+// DefaultCodeLevels returns a copy of the table which maps gRPC codes to levels, it is used when
+// InterceptorConfig.Levels does not contain a code.
+// Codes caused by clients are logged at logx.LevelInfo, codes indicating server problems at logx.LevelError,
+// others at logx.LevelWarn. Codes not in the table are logged at logx.LevelError.
+func DefaultCodeLevels() map[codes.Code]logx.Level {
+	res := make(map[codes.Code]logx.Level, len(defaultCodeLevels))
+	for code, level := range defaultCodeLevels {
+		res[code] = level
+	}
+	return res
+}
+
+// InterceptorConfig configures the interceptors.
+type InterceptorConfig struct {
+	// Logger receives the log messages.
+	// For server interceptors, if it is nil, logx.NopLogger is used.
+	// For client interceptors, if it is nil, the Logger in the context, see logx.FromContext(), is used;
+	// if there is none, nothing is logged.
+	Logger logx.Logger
+
+	// Message is the message of the log messages. If it is empty, DefaultServerMessage or DefaultClientMessage
+	// is used.
+	Message string
+
+	// Levels maps gRPC codes to levels, it overrides DefaultCodeLevels().
+	Levels map[codes.Code]logx.Level
+
+	// MetadataKeys are the keys of the metadata to be logged, e.g. 'x-request-id'.
+	// The incoming metadata is used by server interceptors, the outgoing metadata by client interceptors.
+	// Each value is logged under its key, multiple values are joined by commas.
+	MetadataKeys []string
+
+	// Now returns the current time. If it is nil, time.Now is used.
+	// It is useful for testing.
+	Now func() time.Time
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor which logs each call after it is handled,
+// with the key-value pairs: method, peer, the metadata in config.MetadataKeys, code, duration, and error if any.
+// The level is decided by the code, see InterceptorConfig.Levels.
+//
+// A request-scoped Logger, which is config.Logger with the method, peer and metadata bound by logx.With(),
+// is stored in the context passed to the handler, it can be retrieved by logx.FromContext().
+func UnaryServerInterceptor(config InterceptorConfig) grpc.UnaryServerInterceptor {
+	config = normalizeServerConfig(config)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := config.Now()
+		fields := serverFields(ctx, info.FullMethod, config)
+		ctx = logx.NewContext(ctx, logx.With(config.Logger, fields...))
+
+		resp, err := handler(ctx, req)
+		logCall(config.Logger, config, fields, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor which logs each stream after it is handled,
+// see UnaryServerInterceptor().
+func StreamServerInterceptor(config InterceptorConfig) grpc.StreamServerInterceptor {
+	config = normalizeServerConfig(config)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := config.Now()
+		fields := serverFields(ss.Context(), info.FullMethod, config)
+		ctx := logx.NewContext(ss.Context(), logx.With(config.Logger, fields...))
+
+		err := handler(srv, &serverStream{ss, ctx})
+		logCall(config.Logger, config, fields, start, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor which logs each call after it completes,
+// with the key-value pairs: method, target, the metadata in config.MetadataKeys, code, duration, and error if any.
+// The level is decided by the code, see InterceptorConfig.Levels.
+func UnaryClientInterceptor(config InterceptorConfig) grpc.UnaryClientInterceptor {
+	config = normalizeClientConfig(config)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		logger := clientLogger(ctx, config)
+		if logger == nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		start := config.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logCall(logger, config, clientFields(ctx, method, cc, config), start, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a grpc.StreamClientInterceptor which logs each stream when it ends,
+// that is, when RecvMsg() returns an error, io.EOF is treated as codes.OK; for streams without server streaming,
+// such as client-streaming calls, when the response is received by RecvMsg(); when SendMsg() or CloseSend()
+// returns an error other than io.EOF; when the context of the call is done, even if the stream is not read any more;
+// or when the stream cannot be created. See UnaryClientInterceptor().
+//
+// If the context of the call can be done, a goroutine waits for it until the stream ends.
+func StreamClientInterceptor(config InterceptorConfig) grpc.StreamClientInterceptor {
+	config = normalizeClientConfig(config)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		logger := clientLogger(ctx, config)
+		if logger == nil {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		start := config.Now()
+		fields := clientFields(ctx, method, cc, config)
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logCall(logger, config, fields, start, err)
+			return cs, err
+		}
+
+		s := &clientStream{ClientStream: cs, serverStreams: desc.ServerStreams, done: make(chan struct{}), finish: func(err error) {
+			if err == io.EOF {
+				err = nil
+			}
+			logCall(logger, config, fields, start, err)
+		}}
+
+		// The context of the stream is canceled when the stream finishes, use the context of the call.
+		if ctx.Done() != nil {
+			go s.watch(ctx)
+		}
+		return s, nil
+	}
+}
+
+// normalizeServerConfig fills the default values of the config for server interceptors.
+func normalizeServerConfig(config InterceptorConfig) InterceptorConfig {
+	if config.Logger == nil {
+		config.Logger = logx.NopLogger
+	}
+	if config.Message == "" {
+		config.Message = DefaultServerMessage
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return config
+}
+
+// normalizeClientConfig fills the default values of the config for client interceptors.
+func normalizeClientConfig(config InterceptorConfig) InterceptorConfig {
+	if config.Message == "" {
+		config.Message = DefaultClientMessage
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return config
+}
+
+// clientLogger returns the Logger for client interceptors, returns nil if there is none.
+func clientLogger(ctx context.Context, config InterceptorConfig) logx.Logger {
+	if config.Logger != nil {
+		return config.Logger
+	}
+	return logx.FromContext(ctx)
+}
+
+// serverFields returns the key-values describing the call on the server side.
+func serverFields(ctx context.Context, method string, config InterceptorConfig) []interface{} {
+	fields := []interface{}{"method", method}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, "peer", p.Addr.String())
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	return appendMetadata(fields, md, config.MetadataKeys)
+}
+
+// clientFields returns the key-values describing the call on the client side.
+func clientFields(ctx context.Context, method string, cc *grpc.ClientConn, config InterceptorConfig) []interface{} {
+	fields := []interface{}{"method", method}
+	if cc != nil {
+		fields = append(fields, "target", cc.Target())
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return appendMetadata(fields, md, config.MetadataKeys)
+}
+
+// appendMetadata appends the values of the given keys in the metadata to the key-values.
+func appendMetadata(keyValues []interface{}, md metadata.MD, keys []string) []interface{} {
+	for _, k := range keys {
+		if v := md.Get(k); len(v) > 0 {
+			keyValues = append(keyValues, k, strings.Join(v, ","))
+		}
+	}
+	return keyValues
+}
+
+// logCall logs the end of a call.
+func logCall(logger logx.Logger, config InterceptorConfig, fields []interface{}, start time.Time, err error) {
+	code := status.Code(err)
+	level := codeLevel(code, config.Levels)
+	if !logx.Enabled(logger, level) {
+		return
+	}
+
+	kv := make([]interface{}, 0, len(fields)+6)
+	kv = append(kv, fields...)
+	kv = append(kv, "code", code.String(), "duration", config.Now().Sub(start))
+	if err != nil {
+		kv = append(kv, "error", err)
+	}
+	logger.Log(level, config.Message, kv...)
+}
+
+// codeLevel returns the level of the code, looks up the given table first, then DefaultCodeLevels().
+func codeLevel(code codes.Code, levels map[codes.Code]logx.Level) logx.Level {
+	if level, ok := levels[code]; ok {
+		return level
+	}
+	if level, ok := defaultCodeLevels[code]; ok {
+		return level
+	}
+	return logx.LevelError
+}
+
+// serverStream replaces the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// clientStream calls finish once when the stream ends.
+type clientStream struct {
+	grpc.ClientStream
+	serverStreams bool // The same as grpc.StreamDesc.ServerStreams.
+	once          sync.Once
+	done          chan struct{} // Closed when the stream ends.
+	finish        func(err error)
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+
+	// io.EOF means the stream is ended by the server, the status is returned by RecvMsg().
+	if err != nil && err != io.EOF {
+		s.end(err)
+	}
+	return err
+}
+
+func (s *clientStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	if err != nil {
+		s.end(err)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	// Without server streaming, there is only one response, the stream ends once it is received.
+	if err != nil || !s.serverStreams {
+		s.end(err)
+	}
+	return err
+}
+
+// watch ends the stream when the context is done.
+func (s *clientStream) watch(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		s.end(status.FromContextError(ctx.Err()).Err())
+	case <-s.done:
+	}
+}
+
+// end calls finish if the stream has not ended.
+func (s *clientStream) end(err error) {
+	s.once.Do(func() {
+		close(s.done)
+		s.finish(err)
+	})
+}
@@ -0,0 +1,227 @@
+package logxgrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	testpb "google.golang.org/grpc/interop/grpc_testing"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func fakeNow() func() time.Time {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+// startServer starts a server serving the health service over bufconn, returns a client connecting to it.
+func startServer(t *testing.T, serverOpts []grpc.ServerOption, dialOpts ...grpc.DialOption) healthpb.HealthClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(serverOpts...)
+	hs := health.NewServer()
+	hs.SetServingStatus("svc", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	dialOpts = append(dialOpts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	cc, err := grpc.NewClient("passthrough:///bufnet", dialOpts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { cc.Close() })
+	return healthpb.NewHealthClient(cc)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	sr := logxtest.NewRecorder()
+	cr := logxtest.NewRecorder()
+
+	// An inner interceptor uses the request-scoped Logger.
+	inner := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		logx.Op(logx.FromContext(ctx)).Info("inner")
+		return handler(ctx, req)
+	}
+
+	client := startServer(t,
+		[]grpc.ServerOption{grpc.ChainUnaryInterceptor(
+			UnaryServerInterceptor(InterceptorConfig{
+				Logger:       sr,
+				MetadataKeys: []string{"x-request-id"},
+				Levels:       map[codes.Code]logx.Level{codes.NotFound: logx.LevelWarn},
+				Now:          fakeNow(),
+			}),
+			inner,
+		)},
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(InterceptorConfig{
+			Logger:       cr,
+			MetadataKeys: []string{"x-request-id"},
+			Now:          fakeNow(),
+		})),
+	)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "rid")
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "svc"})
+	assert.NoError(t, err)
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, codes.NotFound, grpcstatus.Code(err))
+
+	a := assert.New(t)
+	a.Equal(`INFO inner method=/grpc.health.v1.Health/Check peer=bufconn x-request-id=rid
+INFO grpc server call method=/grpc.health.v1.Health/Check peer=bufconn x-request-id=rid code=OK duration=1s
+INFO inner method=/grpc.health.v1.Health/Check peer=bufconn x-request-id=rid
+WARN grpc server call method=/grpc.health.v1.Health/Check peer=bufconn x-request-id=rid code=NotFound duration=1s error=rpc error: code = NotFound desc = unknown service
+`, sr.String())
+	a.Equal(`INFO grpc client call method=/grpc.health.v1.Health/Check target=passthrough:///bufnet x-request-id=rid code=OK duration=1s
+INFO grpc client call method=/grpc.health.v1.Health/Check target=passthrough:///bufnet x-request-id=rid code=NotFound duration=1s error=rpc error: code = NotFound desc = unknown service
+`, cr.String())
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	entries := make(chan logx.Entry, 10)
+	sl := logx.NewHandlerLogger("", logx.HandlerFunc(func(e logx.Entry) error {
+		entries <- e
+		return nil
+	}))
+	cr := logxtest.NewRecorder()
+
+	var scoped logx.Logger
+	inner := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		scoped = logx.FromContext(ss.Context())
+		return handler(srv, ss)
+	}
+
+	client := startServer(t,
+		[]grpc.ServerOption{grpc.ChainStreamInterceptor(StreamServerInterceptor(InterceptorConfig{Logger: sl}), inner)},
+		grpc.WithStreamInterceptor(StreamClientInterceptor(InterceptorConfig{})),
+	)
+
+	ctx, cancel := context.WithCancel(logx.NewContext(context.Background(), cr))
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "svc"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, grpcstatus.Code(err))
+
+	e := <-entries
+	a := assert.New(t)
+	a.Equal(logx.LevelInfo, e.Level)
+	a.Equal(DefaultServerMessage, e.Message)
+	a.Equal([]interface{}{"method", "/grpc.health.v1.Health/Watch", "peer", "bufconn", "code", "Canceled"}, e.KeyValues[:6])
+	a.NotNil(scoped)
+
+	a.Equal(1, len(cr.Messages))
+	a.Equal(logx.LevelInfo, cr.Messages[0].Level)
+	a.Equal(DefaultClientMessage, cr.Messages[0].Message)
+	a.Equal([]interface{}{"method", "/grpc.health.v1.Health/Watch", "target", "passthrough:///bufnet", "code", "Canceled"},
+		cr.Messages[0].KeyValues[:6])
+}
+
+func TestStreamClientInterceptor_contextDone(t *testing.T) {
+	entries := make(chan logx.Entry, 10)
+	cl := logx.NewHandlerLogger("", logx.HandlerFunc(func(e logx.Entry) error {
+		entries <- e
+		return nil
+	}))
+	client := startServer(t, nil, grpc.WithStreamInterceptor(StreamClientInterceptor(InterceptorConfig{Logger: cl})))
+
+	// The stream is abandoned without reading, it is logged when the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "svc"})
+	require.NoError(t, err)
+	cancel()
+
+	select {
+	case e := <-entries:
+		assert.Equal(t, []interface{}{"code", "Canceled"}, e.KeyValues[4:6])
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream is not logged")
+	}
+}
+
+func TestUnaryClientInterceptor_noLogger(t *testing.T) {
+	client := startServer(t, nil, grpc.WithUnaryInterceptor(UnaryClientInterceptor(InterceptorConfig{})))
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "svc"})
+	assert.NoError(t, err)
+}
+
+func TestCodeLevel(t *testing.T) {
+	a := assert.New(t)
+	a.Equal(logx.LevelInfo, codeLevel(codes.OK, nil))
+	a.Equal(logx.LevelError, codeLevel(codes.Internal, nil))
+	a.Equal(logx.LevelDebug, codeLevel(codes.Internal, map[codes.Code]logx.Level{codes.Internal: logx.LevelDebug}))
+	a.Equal(logx.LevelError, codeLevel(codes.Code(100), nil))
+
+	levels := DefaultCodeLevels()
+	levels[codes.OK] = logx.LevelDebug
+	a.Equal(logx.LevelInfo, codeLevel(codes.OK, nil))
+}
+
+// testServer implements the client-streaming method of the grpc testing service.
+type testServer struct {
+	testpb.UnimplementedTestServiceServer
+}
+
+func (testServer) StreamingInputCall(stream testpb.TestService_StreamingInputCallServer) error {
+	var size int32
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&testpb.StreamingInputCallResponse{AggregatedPayloadSize: size})
+		}
+		if err != nil {
+			return err
+		}
+		size += int32(len(req.GetPayload().GetBody()))
+	}
+}
+
+func TestStreamClientInterceptor_clientStreaming(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	testpb.RegisterTestServiceServer(srv, testServer{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	r := logxtest.NewRecorder()
+	cc, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(InterceptorConfig{Logger: r, Now: fakeNow()})),
+	)
+	require.NoError(t, err)
+	defer cc.Close()
+
+	stream, err := testpb.NewTestServiceClient(cc).StreamingInputCall(context.Background())
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stream.Send(&testpb.StreamingInputCallRequest{Payload: &testpb.Payload{Body: []byte("ab")}}))
+	}
+
+	resp, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, int32(6), resp.AggregatedPayloadSize)
+	assert.Equal(t, "INFO grpc client call method=/grpc.testing.TestService/StreamingInputCall target=passthrough:///bufnet code=OK duration=1s\n", r.String())
+}
@@ -0,0 +1,2 @@
+// Package logxhttp integrates net/http with the logx package.
+package logxhttp
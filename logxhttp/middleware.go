@@ -0,0 +1,312 @@
+package logxhttp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cmstar/go-logx"
+)
+
+// DefaultRequestIDHeader is the default value of Config.RequestIDHeader.
+const DefaultRequestIDHeader = "X-Request-Id"
+
+// MaxRequestIDLength is the maximum length of request IDs accepted by ValidRequestID.
+const MaxRequestIDLength = 128
+
+// DefaultMessage is the default value of Config.Message.
+const DefaultMessage = "http request"
+
+// Config configures the middleware created by Middleware.
+type Config struct {
+	// Logger receives the log messages. If it is nil, logx.NopLogger is used.
+	Logger logx.Logger
+
+	// Message is the message of the log messages. If it is empty, DefaultMessage is used.
+	Message string
+
+	// RequestIDHeader is the name of the header carrying the request ID. If it is empty,
+	// DefaultRequestIDHeader is used.
+	RequestIDHeader string
+
+	// GenerateID generates request IDs for requests without a valid RequestIDHeader.
+	// If it is nil, random IDs of 32 hex digits are generated.
+	GenerateID func() string
+
+	// ValidateID reports whether the request ID from the RequestIDHeader is acceptable, a new ID is generated
+	// if it returns false. The ID comes from the client, it is written to logs and the response header as is.
+	// If it is nil, ValidRequestID is used.
+	ValidateID func(id string) bool
+
+	// Level returns the level of the log message by the status code of the response.
+	// If it is nil, DefaultLevel is used.
+	Level func(status int) logx.Level
+
+	// Skip, if not nil, is called for each request, the request is not logged if it returns true.
+	// The request ID and the request-scoped Logger are still available. It is useful for health checks.
+	Skip func(r *http.Request) bool
+
+	// Now returns the current time. If it is nil, time.Now is used.
+	// It is useful for testing.
+	Now func() time.Time
+}
+
+// DefaultLevel is the default value of Config.Level. It returns logx.LevelError for status 5xx,
+// logx.LevelWarn for 4xx, and logx.LevelInfo for others.
+func DefaultLevel(status int) logx.Level {
+	switch {
+	case status >= 500:
+		return logx.LevelError
+	case status >= 400:
+		return logx.LevelWarn
+	default:
+		return logx.LevelInfo
+	}
+}
+
+// ValidRequestID is the default value of Config.ValidateID. It accepts non-empty IDs of at most
+// MaxRequestIDLength ASCII letters, digits and the characters '-', '_', '.', ':'.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// SkipPaths returns a function for Config.Skip, which skips the requests with the given URL paths.
+func SkipPaths(paths ...string) func(r *http.Request) bool {
+	m := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		m[p] = true
+	}
+	return func(r *http.Request) bool {
+		return m[r.URL.Path]
+	}
+}
+
+// Middleware returns a middleware which logs each request after it is served, with the key-value pairs:
+// method, path, status, bytes, duration, remote_addr and request_id.
+//
+// The request ID is read from the request header config.RequestIDHeader, or generated if absent or invalid,
+// see Config.ValidateID, and is written to the same header of the response.
+// If the handler panics, the request is logged with the status 500, unless a status is written,
+// and the key-value pair panic=V, then the panic goes on.
+// A request-scoped Logger, which is config.Logger with the method, path, remote_addr and request_id bound
+// by logx.With(), is stored in the request context, it can be retrieved by logx.FromContext(); the ID can be retrieved by RequestID().
+func Middleware(config Config) func(next http.Handler) http.Handler {
+	if config.Logger == nil {
+		config.Logger = logx.NopLogger
+	}
+	if config.Message == "" {
+		config.Message = DefaultMessage
+	}
+	if config.RequestIDHeader == "" {
+		config.RequestIDHeader = DefaultRequestIDHeader
+	}
+	if config.GenerateID == nil {
+		config.GenerateID = newRequestID
+	}
+	if config.ValidateID == nil {
+		config.ValidateID = ValidRequestID
+	}
+	if config.Level == nil {
+		config.Level = DefaultLevel
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := config.Now()
+
+			id := r.Header.Get(config.RequestIDHeader)
+			if !config.ValidateID(id) {
+				id = config.GenerateID()
+			}
+			w.Header().Set(config.RequestIDHeader, id)
+
+			logger := logx.With(config.Logger,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"remote_addr", r.RemoteAddr,
+				"request_id", id,
+			)
+			ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+			ctx = logx.NewContext(ctx, logger)
+			r = r.WithContext(ctx)
+
+			if config.Skip != nil && config.Skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := &responseWriter{ResponseWriter: w}
+			defer func() {
+				// The request is logged even if the handler panics, then the panic goes on to the server.
+				p := recover()
+
+				status := rw.status
+				if status == 0 {
+					status = http.StatusOK
+					if p != nil {
+						status = http.StatusInternalServerError
+					}
+				}
+
+				kv := []interface{}{
+					"method", r.Method,
+					"path", r.URL.Path,
+					"status", status,
+					"bytes", rw.bytes,
+					"duration", config.Now().Sub(start),
+					"remote_addr", r.RemoteAddr,
+					"request_id", id,
+				}
+				if p != nil {
+					kv = append(kv, "panic", p)
+				}
+				config.Logger.Log(config.Level(status), config.Message, kv...)
+
+				if p != nil {
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(rw.wrap(), r)
+		})
+	}
+}
+
+// requestIDKey is the key of the request ID stored in a context.
+type requestIDKey struct{}
+
+// RequestID returns the request ID stored in the context by the middleware created by Middleware.
+// Returns an empty string if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newRequestID returns a random ID of 32 hex digits.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// responseWriter records the status code and the number of bytes written.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// ReadFrom implements io.ReaderFrom, it keeps the sendfile optimization of the underlying ResponseWriter
+// when io.Copy() is used on the wrapper.
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	var n int64
+	var err error
+	if rf, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		// Hide ReadFrom of the wrapper, or io.Copy() calls it back.
+		n, err = io.Copy(struct{ io.Writer }{w.ResponseWriter}, r)
+	}
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWriter, it is used by http.ResponseController since Go 1.20.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// wrap returns the ResponseWriter passed to the handler, it implements http.Flusher, http.Hijacker
+// and http.Pusher only if the underlying ResponseWriter does, so that handlers checking them
+// by type assertions are not misled. The calls of these methods go to the underlying ResponseWriter.
+func (w *responseWriter) wrap() http.ResponseWriter {
+	f, isFlusher := w.ResponseWriter.(http.Flusher)
+	h, isHijacker := w.ResponseWriter.(http.Hijacker)
+	p, isPusher := w.ResponseWriter.(http.Pusher)
+
+	switch {
+	case isFlusher && isHijacker && isPusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{w, f, h, p}
+	case isFlusher && isHijacker:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Hijacker
+		}{w, f, h}
+	case isFlusher && isPusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+			http.Pusher
+		}{w, f, p}
+	case isHijacker && isPusher:
+		return struct {
+			*responseWriter
+			http.Hijacker
+			http.Pusher
+		}{w, h, p}
+	case isFlusher:
+		return struct {
+			*responseWriter
+			http.Flusher
+		}{w, f}
+	case isHijacker:
+		return struct {
+			*responseWriter
+			http.Hijacker
+		}{w, h}
+	case isPusher:
+		return struct {
+			*responseWriter
+			http.Pusher
+		}{w, p}
+	default:
+		return w
+	}
+}
@@ -0,0 +1,189 @@
+package logxhttp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeNow() func() time.Time {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	r := logxtest.NewRecorder()
+	mw := Middleware(Config{
+		Logger:     r,
+		GenerateID: func() string { return "gen" },
+		Skip:       SkipPaths("/health"),
+		Now:        fakeNow(),
+	})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		logx.Op(logx.FromContext(req.Context())).Info("in " + RequestID(req.Context()))
+		switch req.URL.Path {
+		case "/missing":
+			http.NotFound(w, req)
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte("hello"))
+		}
+	}))
+
+	serve := func(path, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "1.2.3.4:5"
+		if id != "" {
+			req.Header.Set(DefaultRequestIDHeader, id)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	a := assert.New(t)
+	a.Equal("gen", serve("/ok", "").Header().Get(DefaultRequestIDHeader))
+	a.Equal("given", serve("/missing", "given").Header().Get(DefaultRequestIDHeader))
+	a.Equal("gen", serve("/ok", "bad id\n").Header().Get(DefaultRequestIDHeader))
+	serve("/fail", "")
+	a.Equal("hello", serve("/health", "").Body.String())
+
+	a.Equal(`INFO in gen method=GET path=/ok remote_addr=1.2.3.4:5 request_id=gen
+INFO http request method=GET path=/ok status=200 bytes=5 duration=1s remote_addr=1.2.3.4:5 request_id=gen
+INFO in given method=GET path=/missing remote_addr=1.2.3.4:5 request_id=given
+WARN http request method=GET path=/missing status=404 bytes=19 duration=1s remote_addr=1.2.3.4:5 request_id=given
+INFO in gen method=GET path=/ok remote_addr=1.2.3.4:5 request_id=gen
+INFO http request method=GET path=/ok status=200 bytes=5 duration=1s remote_addr=1.2.3.4:5 request_id=gen
+INFO in gen method=GET path=/fail remote_addr=1.2.3.4:5 request_id=gen
+ERROR http request method=GET path=/fail status=500 bytes=0 duration=1s remote_addr=1.2.3.4:5 request_id=gen
+INFO in gen method=GET path=/health remote_addr=1.2.3.4:5 request_id=gen
+`, r.String())
+}
+
+func TestMiddleware_config(t *testing.T) {
+	r := logxtest.NewRecorder()
+	mw := Middleware(Config{
+		Logger:          r,
+		Message:         "req",
+		RequestIDHeader: "X-Trace",
+		Level:           func(int) logx.Level { return logx.LevelDebug },
+	})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.(http.Flusher).Flush()
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/p", nil))
+
+	a := assert.New(t)
+	a.Len(w.Header().Get("X-Trace"), 32)
+	a.True(w.Flushed)
+	a.Equal(1, len(r.Messages))
+	a.Equal(logx.LevelDebug, r.Messages[0].Level)
+	a.Equal("req", r.Messages[0].Message)
+	a.Equal(http.StatusOK, r.Messages[0].KeyValues[5])
+}
+
+func TestValidRequestID(t *testing.T) {
+	a := assert.New(t)
+	a.True(ValidRequestID("a-Z_0.9:x"))
+	a.True(ValidRequestID(strings.Repeat("a", MaxRequestIDLength)))
+	a.False(ValidRequestID(""))
+	a.False(ValidRequestID(strings.Repeat("a", MaxRequestIDLength+1)))
+	a.False(ValidRequestID("a b"))
+	a.False(ValidRequestID("a\nb"))
+	a.False(ValidRequestID("中"))
+}
+
+func TestMiddleware_responseWriter(t *testing.T) {
+	mw := Middleware(Config{})
+
+	t.Run("ReadFrom", func(t *testing.T) {
+		r := logxtest.NewRecorder()
+		h := Middleware(Config{Logger: r})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			n, err := w.(io.ReaderFrom).ReadFrom(strings.NewReader("hello"))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(5), n)
+		}))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "hello", w.Body.String())
+		assert.Equal(t, []interface{}{"status", http.StatusOK, "bytes", int64(5)}, r.Messages[0].KeyValues[4:8])
+	})
+
+	t.Run("notSupported", func(t *testing.T) {
+		h := mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, isHijacker := w.(http.Hijacker)
+			_, isPusher := w.(http.Pusher)
+			_, isFlusher := w.(http.Flusher)
+			assert.False(t, isHijacker)
+			assert.False(t, isPusher)
+			assert.True(t, isFlusher)
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		h = mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, isFlusher := w.(http.Flusher)
+			assert.False(t, isFlusher)
+		}))
+		h.ServeHTTP(struct{ http.ResponseWriter }{httptest.NewRecorder()}, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	t.Run("Hijack", func(t *testing.T) {
+		srv := httptest.NewServer(mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+			buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+			buf.Flush()
+		})))
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "hijacked", string(body))
+	})
+}
+
+func TestMiddleware_panic(t *testing.T) {
+	r := logxtest.NewRecorder()
+	h := Middleware(Config{Logger: r})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		panic("boom")
+	}))
+
+	a := assert.New(t)
+	a.PanicsWithValue("boom", func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	if a.Len(r.Messages, 1) {
+		m := r.Messages[0]
+		a.Equal(logx.LevelError, m.Level)
+		a.Equal([]interface{}{"status", http.StatusInternalServerError}, m.KeyValues[4:6])
+		a.Equal([]interface{}{"panic", "boom"}, m.KeyValues[len(m.KeyValues)-2:])
+	}
+}
+
+func TestRequestID_none(t *testing.T) {
+	assert.Equal(t, "", RequestID(httptest.NewRequest(http.MethodGet, "/", nil).Context()))
+}
@@ -0,0 +1,310 @@
+package logxhttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cmstar/go-logx"
+)
+
+// DefaultTransportMessage is the default value of TransportConfig.Message.
+const DefaultTransportMessage = "http client request"
+
+// DefaultRedactedHeaders is the default value of TransportConfig.RedactedHeaders.
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// DefaultRedactedQueryParams is the default value of TransportConfig.RedactedQueryParams.
+var DefaultRedactedQueryParams = []string{"access_token", "token", "api_key", "apikey", "password", "secret", "signature"}
+
+// TransportConfig configures the http.RoundTripper created by NewTransport.
+type TransportConfig struct {
+	// Logger receives the log messages. If it is nil, the Logger in the request context, see logx.FromContext(),
+	// is used; if there is none, nothing is logged.
+	Logger logx.Logger
+
+	// Message is the message of the log messages. If it is empty, DefaultTransportMessage is used.
+	Message string
+
+	// Level returns the level of the log message by the status code of the response.
+	// If it is nil, DefaultLevel is used. Failed requests are always logged at logx.LevelError.
+	Level func(status int) logx.Level
+
+	// Headers, if true, makes the request and response headers be logged.
+	Headers bool
+
+	// RedactedHeaders are the names of headers whose values are replaced with logx.DefaultRedactMask.
+	// If it is nil, DefaultRedactedHeaders is used.
+	RedactedHeaders []string
+
+	// RedactedQueryParams are the names of query parameters whose values are replaced with logx.DefaultRedactMask
+	// in the logged URL, names are case-insensitive. If it is nil, DefaultRedactedQueryParams is used.
+	// The password of the user info in the URL is always redacted.
+	RedactedQueryParams []string
+
+	// MaxBodyBytes, if positive, makes the first MaxBodyBytes bytes of the request and response bodies be logged.
+	// The request body is read ahead before the request is sent. The response body is captured while the caller
+	// reads it, the log message is written when the body reaches EOF or is closed, whichever comes first;
+	// streaming responses, with the content type text/event-stream or the status 101, are logged
+	// when RoundTrip() returns, without the response body.
+	MaxBodyBytes int
+
+	// Now returns the current time. If it is nil, time.Now is used.
+	// It is useful for testing.
+	Now func() time.Time
+}
+
+// NewTransport wraps the given http.RoundTripper, returns a new http.RoundTripper which logs each request
+// with the key-value pairs: method, url, status, duration; and error if the request failed.
+// If next is nil, http.DefaultTransport is used.
+//
+// With config.Headers, request_headers and response_headers are added; with config.MaxBodyBytes,
+// request_body and response_body are added, and the message is written after the response body is read,
+// see TransportConfig.MaxBodyBytes.
+func NewTransport(next http.RoundTripper, config TransportConfig) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if config.Message == "" {
+		config.Message = DefaultTransportMessage
+	}
+	if config.Level == nil {
+		config.Level = DefaultLevel
+	}
+	if config.RedactedHeaders == nil {
+		config.RedactedHeaders = DefaultRedactedHeaders
+	}
+	if config.RedactedQueryParams == nil {
+		config.RedactedQueryParams = DefaultRedactedQueryParams
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	redacted := make(map[string]bool, len(config.RedactedHeaders))
+	for _, h := range config.RedactedHeaders {
+		redacted[http.CanonicalHeaderKey(h)] = true
+	}
+
+	redactedParams := make(map[string]bool, len(config.RedactedQueryParams))
+	for _, p := range config.RedactedQueryParams {
+		redactedParams[strings.ToLower(p)] = true
+	}
+	return &transport{next, config, redacted, redactedParams}
+}
+
+// transport is the http.RoundTripper created by NewTransport.
+type transport struct {
+	next           http.RoundTripper
+	config         TransportConfig
+	redacted       map[string]bool // Canonical names of the redacted headers.
+	redactedParams map[string]bool // Lowercase names of the redacted query parameters.
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := t.config.Logger
+	if logger == nil {
+		logger = logx.FromContext(req.Context())
+	}
+	if logger == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if t.config.MaxBodyBytes > 0 && req.Body != nil && req.Body != http.NoBody {
+		// RoundTrippers must not modify the request, use a shallow copy with a new body.
+		cp := *req
+		reqBody, cp.Body = peekBody(req.Body, t.config.MaxBodyBytes)
+		req = &cp
+	}
+
+	start := t.config.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := t.config.Now().Sub(start)
+
+	kv := []interface{}{
+		"method", req.Method,
+		"url", t.redactURL(req.URL),
+	}
+
+	if err != nil {
+		kv = append(kv, "duration", duration, "error", err)
+		kv = t.appendDetails(kv, req, nil, reqBody, nil)
+		logger.Log(logx.LevelError, t.config.Message, kv...)
+		return resp, err
+	}
+
+	kv = append(kv, "status", resp.StatusCode, "duration", duration)
+	level := t.config.Level(resp.StatusCode)
+	if t.config.MaxBodyBytes <= 0 || resp.Body == nil || resp.Body == http.NoBody || isStreaming(resp) {
+		kv = t.appendDetails(kv, req, resp, reqBody, nil)
+		logger.Log(level, t.config.Message, kv...)
+		return resp, nil
+	}
+
+	resp.Body = &capturedBody{
+		body: resp.Body,
+		max:  t.config.MaxBodyBytes,
+		log: func(respBody []byte) {
+			kv = t.appendDetails(kv, req, resp, reqBody, respBody)
+			logger.Log(level, t.config.Message, kv...)
+		},
+	}
+	return resp, nil
+}
+
+// isStreaming reports whether the response is a stream which may not end soon, its body is not captured.
+func isStreaming(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		return true
+	}
+
+	mediaType := resp.Header.Get("Content-Type")
+	if i := strings.IndexByte(mediaType, ';'); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	return strings.EqualFold(strings.TrimSpace(mediaType), "text/event-stream")
+}
+
+// appendDetails appends the headers and bodies to the key-values, as configured. resp can be nil.
+func (t *transport) appendDetails(kv []interface{}, req *http.Request, resp *http.Response, reqBody, respBody []byte) []interface{} {
+	if t.config.Headers {
+		kv = append(kv, "request_headers", t.redactHeader(req.Header))
+		if resp != nil {
+			kv = append(kv, "response_headers", t.redactHeader(resp.Header))
+		}
+	}
+
+	if t.config.MaxBodyBytes > 0 {
+		kv = append(kv, "request_body", string(reqBody))
+		if resp != nil {
+			kv = append(kv, "response_body", string(respBody))
+		}
+	}
+	return kv
+}
+
+// redactURL returns the URL as a string, with the password and the values of the redacted query parameters masked.
+// The order of the query parameters is kept.
+func (t *transport) redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Redacted()
+	}
+
+	cp := *u
+	params := strings.Split(cp.RawQuery, "&")
+	for i, param := range params {
+		rawName := param
+		if j := strings.IndexByte(param, '='); j >= 0 {
+			rawName = param[:j]
+		}
+
+		name := rawName
+		if unescaped, err := url.QueryUnescape(rawName); err == nil {
+			name = unescaped
+		}
+		if t.redactedParams[strings.ToLower(name)] {
+			params[i] = rawName + "=" + logx.DefaultRedactMask
+		}
+	}
+	cp.RawQuery = strings.Join(params, "&")
+	return cp.Redacted()
+}
+
+// redactHeader returns a copy of the header, values of multiple lines are joined by commas,
+// values of the redacted headers are masked.
+func (t *transport) redactHeader(h http.Header) map[string]string {
+	res := make(map[string]string, len(h))
+	for k, v := range h {
+		if t.redacted[http.CanonicalHeaderKey(k)] {
+			res[k] = logx.DefaultRedactMask
+		} else {
+			res[k] = strings.Join(v, ", ")
+		}
+	}
+	return res
+}
+
+// peekBody reads at most n bytes from the body, returns the bytes read and a new body
+// which produces the whole content of the original one.
+func peekBody(body io.ReadCloser, n int) ([]byte, io.ReadCloser) {
+	buf := make([]byte, n)
+	read, err := io.ReadFull(body, buf)
+	buf = buf[:read]
+
+	var rest io.Reader = body
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		rest = errReader{err}
+	}
+
+	return buf, readCloser{io.MultiReader(bytes.NewReader(buf), rest), body}
+}
+
+// capturedBody wraps a response body, keeps the first max bytes read,
+// calls log with them once when the body reaches EOF or is closed.
+type capturedBody struct {
+	body io.ReadCloser
+	max  int
+	log  func(respBody []byte)
+
+	mu     sync.Mutex // Close() can be called concurrently with Read() to cancel it.
+	buf    []byte
+	logged bool
+}
+
+func (b *capturedBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+
+	b.mu.Lock()
+	if rest := b.max - len(b.buf); rest > 0 && n > 0 {
+		if rest > n {
+			rest = n
+		}
+		b.buf = append(b.buf, p[:rest]...)
+	}
+	b.mu.Unlock()
+
+	if err == io.EOF {
+		b.done()
+	}
+	return n, err
+}
+
+func (b *capturedBody) Close() error {
+	err := b.body.Close()
+	b.done()
+	return err
+}
+
+// done calls log if it is not called yet.
+func (b *capturedBody) done() {
+	b.mu.Lock()
+	if b.logged {
+		b.mu.Unlock()
+		return
+	}
+	b.logged = true
+	buf := b.buf
+	b.mu.Unlock()
+
+	b.log(buf)
+}
+
+// readCloser combines a Reader and a Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// errReader is a Reader which always returns the error.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,207 @@
+package logxhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "s=1")
+		w.Header().Set("X-Echo", r.Header.Get("X-Echo"))
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/fail":
+			w.WriteHeader(http.StatusBadGateway)
+		}
+		w.Write([]byte("echo:"))
+		w.Write(body)
+	}))
+}
+
+func TestTransport(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	r := logxtest.NewRecorder()
+	client := &http.Client{Transport: NewTransport(nil, TransportConfig{
+		Logger: r,
+		Now:    fakeNow(),
+	})}
+
+	for _, p := range []string{"/ok", "/missing", "/fail"} {
+		resp, err := client.Get(srv.URL + p)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	a := assert.New(t)
+	a.Equal(3, len(r.Messages))
+	a.Equal(`INFO http client request method=GET url=`+srv.URL+`/ok status=200 duration=1s
+WARN http client request method=GET url=`+srv.URL+`/missing status=404 duration=1s
+ERROR http client request method=GET url=`+srv.URL+`/fail status=502 duration=1s
+`, r.String())
+}
+
+func TestTransport_details(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	r := logxtest.NewRecorder()
+	client := &http.Client{Transport: NewTransport(nil, TransportConfig{
+		Logger:          r,
+		Headers:         true,
+		RedactedHeaders: []string{"x-secret", "set-cookie"},
+		MaxBodyBytes:    8,
+		Now:             fakeNow(),
+	})}
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("0123456789"))
+	req.Header.Set("X-Secret", "s")
+	req.Header.Set("X-Echo", "e")
+	resp, err := client.Do(req)
+
+	a := assert.New(t)
+	a.NoError(err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	a.Equal("echo:0123456789", string(body))
+
+	a.Equal(1, len(r.Messages))
+	kv := r.Messages[0].KeyValues
+	a.Equal("request_headers", kv[8])
+	a.Equal("***", kv[9].(map[string]string)["X-Secret"])
+	a.Equal("e", kv[9].(map[string]string)["X-Echo"])
+	a.Equal("response_headers", kv[10])
+	a.Equal("***", kv[11].(map[string]string)["Set-Cookie"])
+	a.Equal("e", kv[11].(map[string]string)["X-Echo"])
+	a.Equal([]interface{}{"request_body", "01234567", "response_body", "echo:012"}, kv[12:])
+}
+
+func TestTransport_lazyBody(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/events" {
+			w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		}
+		w.Write([]byte("data: 1\n\n"))
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	r := logxtest.NewRecorder()
+	client := &http.Client{Transport: NewTransport(nil, TransportConfig{
+		Logger:       r,
+		MaxBodyBytes: 4,
+		Now:          fakeNow(),
+	})}
+
+	a := assert.New(t)
+
+	// The stream is logged without waiting for the body.
+	resp, err := client.Get(srv.URL + "/events")
+	if a.NoError(err) {
+		a.Equal(1, len(r.Messages))
+		a.Equal([]interface{}{"response_body", ""}, r.Messages[0].KeyValues[10:])
+		resp.Body.Close()
+	}
+
+	// The body is still streaming, the message is written on Close().
+	resp, err = client.Get(srv.URL + "/slow")
+	if a.NoError(err) {
+		buf := make([]byte, 9)
+		_, err = io.ReadFull(resp.Body, buf)
+		a.NoError(err)
+		a.Equal(1, len(r.Messages))
+
+		resp.Body.Close()
+		resp.Body.Close()
+		a.Equal(2, len(r.Messages))
+		a.Equal([]interface{}{"response_body", "data"}, r.Messages[1].KeyValues[10:])
+	}
+}
+
+func TestTransport_redactURL(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	for _, c := range []struct {
+		name   string
+		params []string
+		url    string
+		want   string
+	}{
+		{"noQuery", nil, "http://u:p@" + host + "/ok", "http://u:xxxxx@" + host + "/ok"},
+		{"default", nil, "http://" + host + "/ok?a=1&Access_Token=t&token&b=2",
+			"http://" + host + "/ok?a=1&Access_Token=***&token=***&b=2"},
+		{"escaped", nil, "http://" + host + "/ok?api%5Fkey=k", "http://" + host + "/ok?api%5Fkey=***"},
+		{"custom", []string{"a"}, "http://u:p@" + host + "/ok?a=1&token=t",
+			"http://u:xxxxx@" + host + "/ok?a=***&token=t"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			r := logxtest.NewRecorder()
+			client := &http.Client{Transport: NewTransport(nil, TransportConfig{
+				Logger:              r,
+				RedactedQueryParams: c.params,
+			})}
+
+			resp, err := client.Get(c.url)
+			assert.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, 1, len(r.Messages))
+			assert.Equal(t, c.want, r.Messages[0].KeyValues[3])
+		})
+	}
+}
+
+func TestTransport_error(t *testing.T) {
+	srv := newTestServer()
+	srv.Close()
+
+	r := logxtest.NewRecorder()
+	client := &http.Client{Transport: NewTransport(nil, TransportConfig{Logger: r})}
+	_, err := client.Get(srv.URL)
+
+	a := assert.New(t)
+	a.Error(err)
+	a.Equal(1, len(r.Messages))
+	a.Equal(logx.LevelError, r.Messages[0].Level)
+	a.Equal("error", r.Messages[0].KeyValues[6])
+}
+
+func TestTransport_contextLogger(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport, TransportConfig{})}
+
+	// No Logger.
+	resp, err := client.Get(srv.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	r := logxtest.NewRecorder()
+	ctx := logx.NewContext(context.Background(), logx.With(r, "request_id", "id"))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err = client.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 1, len(r.Messages))
+	assert.Equal(t, []interface{}{"request_id", "id", "method", "GET"}, r.Messages[0].KeyValues[:4])
+}
@@ -0,0 +1,15 @@
+module github.com/cmstar/go-logx/logxlogr
+
+go 1.18
+
+require (
+	github.com/cmstar/go-logx v1.3.0
+	github.com/go-logr/logr v1.4.2
+	github.com/stretchr/testify v1.7.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
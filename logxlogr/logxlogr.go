@@ -0,0 +1,123 @@
+// Package logxlogr provides a github.com/go-logr/logr LogSink backed by the logx package,
+// so components requiring logr, such as Kubernetes controllers, can share the loggers managed by logx.
+package logxlogr
+
+import (
+	"github.com/cmstar/go-logx"
+	"github.com/go-logr/logr"
+)
+
+// ErrorKey is the key of the error passed to logr.Logger.Error().
+const ErrorKey = logx.ErrorKey
+
+// DefaultLevels is the default value of Config.Levels, V(0) is mapped to logx.LevelInfo,
+// V(1) and higher verbosities are mapped to logx.LevelDebug.
+var DefaultLevels = []logx.Level{logx.LevelInfo, logx.LevelDebug}
+
+// Config configures the LogSink created by NewSink.
+type Config struct {
+	// Levels maps logr verbosities to logx levels, V(i) is mapped to Levels[i];
+	// verbosities beyond the table are mapped to the last element.
+	// If it is empty, DefaultLevels is used.
+	Levels []logx.Level
+
+	// Name is the initial name of the LogSink, which is used to find the logx.Logger.
+	Name string
+}
+
+// sink is a logr.LogSink which writes log messages to the loggers found by a logx.LogFinder.
+type sink struct {
+	finder    logx.LogFinder
+	levels    []logx.Level
+	name      string
+	keyValues []interface{} // The key-values bound by WithValues().
+}
+
+// New creates a logr.Logger with the LogSink created by NewSink.
+func New(finder logx.LogFinder, config Config) logr.Logger {
+	return logr.New(NewSink(finder, config))
+}
+
+// NewSink creates a logr.LogSink which writes log messages to the logx.Logger found by the given
+// logx.LogFinder, such as a *logx.LogManager, with the name of the LogSink.
+// If no logger can be found, log messages are dropped.
+// Enabled() maps the verbosity to a level and checks it with logx.Enabled().
+// To use a single logx.Logger, use logx.NewSingleLoggerLogFinder().
+//
+// WithName() joins the names with dots, e.g. logger.WithName("a").WithName("b") finds the logger 'a.b',
+// which is resolved as described in logx.LogManager.
+//
+// WithValues() binds key-values, which are put before the key-values of each log message.
+// Error() logs at logx.LevelError with the error under the key ErrorKey.
+func NewSink(finder logx.LogFinder, config Config) logr.LogSink {
+	levels := config.Levels
+	if len(levels) == 0 {
+		levels = DefaultLevels
+	}
+	return &sink{
+		finder: finder,
+		levels: levels,
+		name:   config.Name,
+	}
+}
+
+func (s *sink) Init(info logr.RuntimeInfo) {
+}
+
+func (s *sink) Enabled(level int) bool {
+	return logx.Enabled(s.finder.Find(s.name), s.level(level))
+}
+
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	logger := s.finder.Find(s.name)
+	if logger == nil {
+		return
+	}
+
+	logger.Log(s.level(level), msg, s.join(nil, keysAndValues)...)
+}
+
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	logger := s.finder.Find(s.name)
+	if logger == nil {
+		return
+	}
+
+	logger.Log(logx.LevelError, msg, s.join([]interface{}{ErrorKey, err}, keysAndValues)...)
+}
+
+func (s *sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	cp := *s
+	cp.keyValues = s.join(nil, keysAndValues)
+	return &cp
+}
+
+func (s *sink) WithName(name string) logr.LogSink {
+	cp := *s
+	if s.name == "" {
+		cp.name = name
+	} else {
+		cp.name = s.name + "." + name
+	}
+	return &cp
+}
+
+// level maps a logr verbosity to a logx.Level.
+func (s *sink) level(v int) logx.Level {
+	if v < 0 {
+		v = 0
+	}
+	if v >= len(s.levels) {
+		v = len(s.levels) - 1
+	}
+	return s.levels[v]
+}
+
+// join returns a new slice with the bound key-values, followed by a and b.
+func (s *sink) join(a, b []interface{}) []interface{} {
+	res := make([]interface{}, 0, len(s.keyValues)+len(a)+len(b))
+	res = append(res, s.keyValues...)
+	res = append(res, a...)
+	res = append(res, b...)
+	return res
+}
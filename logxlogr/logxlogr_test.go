@@ -0,0 +1,75 @@
+package logxlogr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSink(t *testing.T) {
+	root := logxtest.NewRecorder()
+	ab := logxtest.NewRecorder()
+	m := logx.NewManager()
+	m.Set("", root)
+	m.Set("a.b", ab)
+
+	logger := New(m, Config{})
+	logger.Info("root", "k", 1)
+	logger.V(1).Info("v1")
+
+	la := logger.WithName("a").WithValues("x", 1)
+	la.V(5).Info("a-v5")
+
+	lab := la.WithName("B").WithValues("y", 2)
+	lab.Info("ab", "k", "v")
+	lab.Error(errors.New("e"), "ab-err", "k", "v")
+
+	a := assert.New(t)
+	a.Equal(`INFO root k=1
+DEBUG v1
+DEBUG a-v5 x=1
+`, root.String())
+	a.Equal(`INFO ab x=1 y=2 k=v
+ERROR ab-err x=1 y=2 error=e k=v
+`, ab.String())
+}
+
+func TestSink_levels(t *testing.T) {
+	r := logxtest.NewRecorder()
+	logger := New(logx.NewSingleLoggerLogFinder(r), Config{
+		Levels: []logx.Level{logx.LevelWarn, logx.LevelInfo, logx.LevelDebug},
+		Name:   "n",
+	})
+
+	logger.Info("v0")
+	logger.V(1).Info("v1")
+	logger.V(2).Info("v2")
+	logger.V(3).Info("v3")
+
+	assert.Equal(t, "WARN v0\nINFO v1\nDEBUG v2\nDEBUG v3\n", r.String())
+}
+
+func TestSink_Enabled(t *testing.T) {
+	r := logx.FilterLevel(logxtest.NewRecorder(), logx.LevelBeyondInfo)
+	logger := New(logx.NewSingleLoggerLogFinder(r), Config{})
+
+	assert.True(t, logger.Enabled())
+	assert.False(t, logger.V(1).Enabled())
+}
+
+func TestSink_notFound(t *testing.T) {
+	m := logx.NewManager()
+	logger := New(m, Config{})
+
+	assert.False(t, logger.Enabled())
+	logger.Info("i")
+	logger.Error(errors.New("e"), "e")
+
+	r := logxtest.NewRecorder()
+	m.Set("x", r)
+	assert.False(t, logger.Enabled())
+	assert.True(t, logger.WithName("x").WithName("y").Enabled())
+}
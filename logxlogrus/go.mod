@@ -0,0 +1,9 @@
+module github.com/cmstar/go-logx/logxlogrus
+
+go 1.16
+
+require (
+	github.com/cmstar/go-logx v1.3.0
+	github.com/sirupsen/logrus v1.9.3
+	github.com/stretchr/testify v1.7.0
+)
@@ -0,0 +1,50 @@
+package logxlogrus
+
+import (
+	"sort"
+
+	"github.com/cmstar/go-logx"
+	"github.com/sirupsen/logrus"
+)
+
+// hook is a logrus.Hook which forwards logrus entries to a logx.Logger.
+type hook struct {
+	logger logx.Logger
+	levels []logrus.Level
+}
+
+// NewHook creates a logrus.Hook which forwards logrus entries to the given logx.Logger.
+// Levels are mapped with FromLogrusLevel(). Fields of the entries are converted to key-value pairs,
+// sorted by keys.
+//
+// levels are the levels the hook fires for, if it is empty, logrus.AllLevels is used.
+//
+// To send all messages of a *logrus.Logger to logx only, add the hook and discard the original output:
+//
+//	logger.AddHook(logxlogrus.NewHook(logxLogger, nil))
+//	logger.SetOutput(io.Discard)
+func NewHook(logger logx.Logger, levels []logrus.Level) logrus.Hook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+	return &hook{logger, levels}
+}
+
+func (h *hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *hook) Fire(entry *logrus.Entry) error {
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	keyValues := make([]interface{}, 0, len(keys)*2)
+	for _, k := range keys {
+		keyValues = append(keyValues, k, entry.Data[k])
+	}
+
+	return h.logger.Log(FromLogrusLevel(entry.Level), entry.Message, keyValues...)
+}
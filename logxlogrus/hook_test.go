@@ -0,0 +1,39 @@
+package logxlogrus
+
+import (
+	"io"
+	"testing"
+
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHook(t *testing.T) {
+	r := logxtest.NewRecorder()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.TraceLevel)
+	logger.AddHook(NewHook(r, nil))
+
+	logger.Trace("t")
+	logger.WithFields(logrus.Fields{"b": 2, "a": 1}).Info("i")
+	logger.WithField("k", "v").Warn("w")
+
+	assert.Equal(t, `TRACE t
+INFO i a=1 b=2
+WARN w k=v
+`, r.String())
+}
+
+func TestHook_levels(t *testing.T) {
+	r := logxtest.NewRecorder()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(NewHook(r, []logrus.Level{logrus.ErrorLevel}))
+
+	logger.Info("i")
+	logger.Error("e")
+
+	assert.Equal(t, "ERROR e\n", r.String())
+}
@@ -0,0 +1,134 @@
+// Package logxlogrus adapts github.com/sirupsen/logrus to the logx package.
+//
+// New() and NewEntry() create a logx.Logger which writes log messages to logrus;
+// NewHook() creates a logrus.Hook which forwards logrus entries to a logx.Logger,
+// so code using logrus can migrate to logx incrementally.
+package logxlogrus
+
+import (
+	"fmt"
+
+	"github.com/cmstar/go-logx"
+	"github.com/sirupsen/logrus"
+)
+
+// Logger is a logx.Logger which writes log messages to a *logrus.Entry.
+type Logger struct {
+	// ExitOnFatal, if true, makes the Logger call logrus.Logger.Exit(1) after writing a message at logx.LevelFatal,
+	// which is the behavior of logrus.Logger.Fatal().
+	// By default, it is false, messages at logx.LevelFatal are written at logrus.FatalLevel without exiting.
+	ExitOnFatal bool
+
+	entry *logrus.Entry
+}
+
+var _ logx.Logger = (*Logger)(nil)
+
+// New creates a new Logger which writes log messages to the given *logrus.Logger.
+func New(logger *logrus.Logger) *Logger {
+	return NewEntry(logrus.NewEntry(logger))
+}
+
+// NewEntry creates a new Logger which writes log messages to the given *logrus.Entry,
+// the fields of the entry are kept.
+//
+// Levels are mapped with ToLogrusLevel(). Key-value pairs are mapped to logrus.Fields,
+// the keys are formatted with fmt.Sprint(); an unpaired value is mapped to a field with key 'UNKNOWN'.
+func NewEntry(entry *logrus.Entry) *Logger {
+	return &Logger{entry: entry}
+}
+
+// Entry returns the underlying *logrus.Entry.
+func (l *Logger) Entry() *logrus.Entry {
+	return l.entry
+}
+
+// Log implements logx.Logger.Log().
+func (l *Logger) Log(level logx.Level, message string, keyValues ...interface{}) error {
+	ll := ToLogrusLevel(level)
+	if !l.entry.Logger.IsLevelEnabled(ll) {
+		return nil
+	}
+
+	entry := l.entry
+	if len(keyValues) > 0 {
+		entry = entry.WithFields(toFields(keyValues))
+	}
+
+	// Entry.Log() neither panics nor exits at the panic or fatal levels.
+	entry.Log(ll, message)
+
+	if ll == logrus.FatalLevel && l.ExitOnFatal {
+		l.entry.Logger.Exit(1)
+	}
+	return nil
+}
+
+// LogFn implements logx.Logger.LogFn().
+// The messageFactory is invoked only if the level is enabled on the *logrus.Logger.
+func (l *Logger) LogFn(level logx.Level, messageFactory func() (string, []interface{})) error {
+	if !l.Enabled(level) {
+		return nil
+	}
+
+	message, keyValues := messageFactory()
+	return l.Log(level, message, keyValues...)
+}
+
+// Enabled implements logx.LevelEnabler.Enabled(), it checks the level with the *logrus.Logger.
+func (l *Logger) Enabled(level logx.Level) bool {
+	return l.entry.Logger.IsLevelEnabled(ToLogrusLevel(level))
+}
+
+// ToLogrusLevel maps a logx.Level to a logrus.Level.
+// If the given level is a combined level, the highest one is used.
+// Unknown levels are mapped to logrus.InfoLevel.
+func ToLogrusLevel(level logx.Level) logrus.Level {
+	switch {
+	case level&logx.LevelFatal != 0:
+		return logrus.FatalLevel
+	case level&logx.LevelError != 0:
+		return logrus.ErrorLevel
+	case level&logx.LevelWarn != 0:
+		return logrus.WarnLevel
+	case level&logx.LevelInfo != 0:
+		return logrus.InfoLevel
+	case level&logx.LevelDebug != 0:
+		return logrus.DebugLevel
+	case level&logx.LevelTrace != 0:
+		return logrus.TraceLevel
+	}
+	return logrus.InfoLevel
+}
+
+// FromLogrusLevel maps a logrus.Level to a logx.Level.
+// logrus.PanicLevel and logrus.FatalLevel are mapped to logx.LevelFatal.
+func FromLogrusLevel(level logrus.Level) logx.Level {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return logx.LevelFatal
+	case logrus.ErrorLevel:
+		return logx.LevelError
+	case logrus.WarnLevel:
+		return logx.LevelWarn
+	case logrus.InfoLevel:
+		return logx.LevelInfo
+	case logrus.DebugLevel:
+		return logx.LevelDebug
+	}
+	return logx.LevelTrace
+}
+
+// toFields converts key-value pairs to logrus.Fields.
+func toFields(keyValues []interface{}) logrus.Fields {
+	length := len(keyValues)
+	fields := make(logrus.Fields, (length+1)/2)
+	for i := 0; i < length-1; i += 2 {
+		fields[fmt.Sprint(keyValues[i])] = keyValues[i+1]
+	}
+
+	if length%2 != 0 {
+		fields["UNKNOWN"] = keyValues[length-1]
+	}
+	return fields
+}
@@ -0,0 +1,77 @@
+package logxlogrus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger() (*logrus.Logger, *bytes.Buffer) {
+	buf := new(bytes.Buffer)
+	logger := logrus.New()
+	logger.SetOutput(buf)
+	logger.SetLevel(logrus.InfoLevel)
+	logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true, DisableColors: true})
+	return logger, buf
+}
+
+func TestLogger(t *testing.T) {
+	logger, buf := newTestLogger()
+	l := NewEntry(logger.WithField("base", 0))
+
+	l.Log(logx.LevelDebug, "d")
+	l.Log(logx.LevelInfo, "i", "k1", 1, 2, "v2", "v3")
+	l.Log(logx.LevelFatal, "f") // Must not exit.
+	l.LogFn(logx.LevelDebug, func() (string, []interface{}) {
+		t.Fatal("the factory should not be invoked")
+		return "", nil
+	})
+	l.LogFn(logx.LevelError, func() (string, []interface{}) { return "e", nil })
+
+	assert.Equal(t, `level=info msg=i 2=v2 UNKNOWN=v3 base=0 k1=1
+level=fatal msg=f base=0
+level=error msg=e base=0
+`, buf.String())
+
+	assert.False(t, logx.Enabled(l, logx.LevelDebug))
+	assert.True(t, logx.Enabled(l, logx.LevelInfo))
+}
+
+func TestLogger_ExitOnFatal(t *testing.T) {
+	logger, buf := newTestLogger()
+	exitCode := -1
+	logger.ExitFunc = func(code int) { exitCode = code }
+
+	l := New(logger)
+	l.Log(logx.LevelError, "e")
+	assert.Equal(t, -1, exitCode)
+
+	l.ExitOnFatal = true
+	l.Log(logx.LevelFatal, "f")
+	assert.Equal(t, 1, exitCode)
+	assert.Equal(t, "level=error msg=e\nlevel=fatal msg=f\n", buf.String())
+	assert.Same(t, logger, l.Entry().Logger)
+}
+
+func TestLevelMapping(t *testing.T) {
+	a := assert.New(t)
+	a.Equal(logrus.TraceLevel, ToLogrusLevel(logx.LevelTrace))
+	a.Equal(logrus.DebugLevel, ToLogrusLevel(logx.LevelDebug))
+	a.Equal(logrus.InfoLevel, ToLogrusLevel(logx.LevelInfo))
+	a.Equal(logrus.WarnLevel, ToLogrusLevel(logx.LevelWarn))
+	a.Equal(logrus.ErrorLevel, ToLogrusLevel(logx.LevelError))
+	a.Equal(logrus.FatalLevel, ToLogrusLevel(logx.LevelFatal))
+	a.Equal(logrus.WarnLevel, ToLogrusLevel(logx.LevelInfo|logx.LevelWarn))
+	a.Equal(logrus.InfoLevel, ToLogrusLevel(0))
+
+	a.Equal(logx.LevelTrace, FromLogrusLevel(logrus.TraceLevel))
+	a.Equal(logx.LevelDebug, FromLogrusLevel(logrus.DebugLevel))
+	a.Equal(logx.LevelInfo, FromLogrusLevel(logrus.InfoLevel))
+	a.Equal(logx.LevelWarn, FromLogrusLevel(logrus.WarnLevel))
+	a.Equal(logx.LevelError, FromLogrusLevel(logrus.ErrorLevel))
+	a.Equal(logx.LevelFatal, FromLogrusLevel(logrus.FatalLevel))
+	a.Equal(logx.LevelFatal, FromLogrusLevel(logrus.PanicLevel))
+}
@@ -0,0 +1,2 @@
+// Package logxotel correlates the logx package with OpenTelemetry traces.
+package logxotel
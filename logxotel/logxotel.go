@@ -0,0 +1,159 @@
+package logxotel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cmstar/go-logx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Keys of the key-value pairs added to log messages.
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// Names of the span events and their attributes, created for log messages.
+const (
+	EventName       = "log"
+	SeverityAttrKey = "log.severity"
+	MessageAttrKey  = "log.message"
+)
+
+// Config configures the Logger created by New and the Middleware created by Middleware.
+type Config struct {
+	// EventLevels is the mask of levels whose messages are also recorded as events of the span, if the span
+	// is recording, e.g. logx.LevelError|logx.LevelFatal. If it is 0, no event is recorded.
+	//
+	// The event is named EventName, with the attributes SeverityAttrKey, MessageAttrKey and the key-values
+	// of the message, values are formatted with fmt.Sprint().
+	//
+	// For the Logger created by New, the event is recorded whether the wrapped Logger filters the level or not,
+	// with Log() and LogFn() alike; the factory of LogFn() is invoked once for both the event and the message.
+	EventLevels logx.Level
+}
+
+// KeyValues returns the key-value pairs trace_id=ID and span_id=ID of the span in the context.
+// Returns nil if there is no valid span context.
+func KeyValues(ctx context.Context) []interface{} {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []interface{}{TraceIDKey, sc.TraceID().String(), SpanIDKey, sc.SpanID().String()}
+}
+
+// New wraps the given Logger, returns a new Logger which adds trace_id and span_id of the span in ctx to
+// each log message, after the key-values of the message; and records events of the span as configured.
+// If ctx carries no valid span context, log messages are passed through unchanged.
+//
+// It is usually created per request, e.g.
+//
+//	logger := logxotel.New(ctx, logger, logxotel.Config{EventLevels: logx.LevelError | logx.LevelFatal})
+func New(ctx context.Context, logger logx.Logger, config Config) logx.Logger {
+	return &spanLogger{logger, ctx, config}
+}
+
+// spanLogger is the Logger created by New.
+type spanLogger struct {
+	logger logx.Logger
+	ctx    context.Context
+	config Config
+}
+
+func (l *spanLogger) Log(level logx.Level, message string, keyValues ...interface{}) error {
+	recordEvent(l.ctx, l.config, level, message, keyValues)
+	return l.logger.Log(level, message, appendTraceKeyValues(l.ctx, keyValues)...)
+}
+
+func (l *spanLogger) LogFn(level logx.Level, messageFactory func() (message string, keyValues []interface{})) error {
+	if !eventEnabled(l.ctx, l.config, level) {
+		return l.logger.LogFn(level, func() (string, []interface{}) {
+			message, keyValues := messageFactory()
+			return message, appendTraceKeyValues(l.ctx, keyValues)
+		})
+	}
+
+	// The event needs the message, even if the wrapped Logger filters the level.
+	message, keyValues := messageFactory()
+	return l.Log(level, message, keyValues...)
+}
+
+// Unwrap implements logx.Unwrapper.Unwrap(), returns the wrapped Logger.
+func (l *spanLogger) Unwrap() logx.Logger {
+	return l.logger
+}
+
+// Enabled implements logx.LevelEnabler.Enabled(), the wrapped Logger decides.
+func (l *spanLogger) Enabled(level logx.Level) bool {
+	return logx.Enabled(l.logger, level)
+}
+
+// Middleware returns a logx.Middleware which does the same thing as the Logger created by New, with the context
+// of each entry, see logx.Entry.Context and logx.HandlerLogger.WithContext().
+func Middleware(config Config) logx.Middleware {
+	return func(next logx.Handler) logx.Handler {
+		return logx.HandlerFunc(func(entry logx.Entry) error {
+			if entry.Context != nil {
+				entry.KeyValues = enrich(entry.Context, config, entry.Level, entry.Message, entry.KeyValues)
+			}
+			return next.Handle(entry)
+		})
+	}
+}
+
+// enrich records the span event if needed, returns a copy of the key-values with the trace_id and span_id.
+func enrich(ctx context.Context, config Config, level logx.Level, message string, keyValues []interface{}) []interface{} {
+	recordEvent(ctx, config, level, message, keyValues)
+	return appendTraceKeyValues(ctx, keyValues)
+}
+
+// eventEnabled reports whether messages at the level are recorded as events of the span in the context.
+func eventEnabled(ctx context.Context, config Config, level logx.Level) bool {
+	if (config.EventLevels & level) != level {
+		return false
+	}
+
+	span := trace.SpanFromContext(ctx)
+	return span.SpanContext().IsValid() && span.IsRecording()
+}
+
+// recordEvent records the message as an event of the span in the context, if it is enabled.
+func recordEvent(ctx context.Context, config Config, level logx.Level, message string, keyValues []interface{}) {
+	if eventEnabled(ctx, config, level) {
+		trace.SpanFromContext(ctx).AddEvent(EventName, trace.WithAttributes(attributes(level, message, keyValues)...))
+	}
+}
+
+// appendTraceKeyValues returns a copy of the key-values with the trace_id and span_id,
+// returns the key-values if there is no valid span context.
+func appendTraceKeyValues(ctx context.Context, keyValues []interface{}) []interface{} {
+	traceKeyValues := KeyValues(ctx)
+	if traceKeyValues == nil {
+		return keyValues
+	}
+
+	res := make([]interface{}, 0, len(keyValues)+len(traceKeyValues))
+	res = append(res, keyValues...)
+	return append(res, traceKeyValues...)
+}
+
+// attributes returns the attributes of the span event.
+func attributes(level logx.Level, message string, keyValues []interface{}) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 2+(len(keyValues)+1)/2)
+	attrs = append(attrs,
+		attribute.String(SeverityAttrKey, logx.LevelToString(level)),
+		attribute.String(MessageAttrKey, message),
+	)
+
+	length := len(keyValues)
+	for i := 0; i < length-1; i += 2 {
+		attrs = append(attrs, attribute.String(fmt.Sprint(keyValues[i]), fmt.Sprint(keyValues[i+1])))
+	}
+	if length%2 != 0 {
+		attrs = append(attrs, attribute.String("UNKNOWN", fmt.Sprint(keyValues[length-1])))
+	}
+	return attrs
+}
@@ -0,0 +1,108 @@
+package logxotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func startSpan(t *testing.T) (context.Context, *tracetest.SpanRecorder) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	ctx, _ := tp.Tracer("test").Start(context.Background(), "op")
+	return ctx, sr
+}
+
+func TestNew(t *testing.T) {
+	ctx, sr := startSpan(t)
+	kv := KeyValues(ctx)
+
+	r := logxtest.NewRecorder()
+	l := New(ctx, r, Config{EventLevels: logx.LevelError})
+	l.Log(logx.LevelInfo, "i", "k", 1)
+	l.LogFn(logx.LevelError, func() (string, []interface{}) { return "e", []interface{}{"k", 2, "odd"} })
+
+	a := assert.New(t)
+	a.Equal([]interface{}{"k", 1, TraceIDKey, kv[1], SpanIDKey, kv[3]}, r.Messages[0].KeyValues)
+	a.Equal([]interface{}{"k", 2, "odd", TraceIDKey, kv[1], SpanIDKey, kv[3]}, r.Messages[1].KeyValues)
+	a.Len(kv[1], 32)
+	a.Len(kv[3], 16)
+
+	a.True(logx.Enabled(l, logx.LevelInfo))
+	a.Same(r, l.(logx.Unwrapper).Unwrap())
+
+	// The span is still recording, read the events from the started span.
+	span := sr.Started()[0]
+	a.Empty(sr.Ended())
+	a.Equal(1, len(span.(sdktrace.ReadWriteSpan).Events()))
+
+	event := span.(sdktrace.ReadWriteSpan).Events()[0]
+	a.Equal(EventName, event.Name)
+	a.Equal([]attribute.KeyValue{
+		attribute.String(SeverityAttrKey, "ERROR"),
+		attribute.String(MessageAttrKey, "e"),
+		attribute.String("k", "2"),
+		attribute.String("UNKNOWN", "odd"),
+	}, event.Attributes)
+}
+
+func TestNew_filtered(t *testing.T) {
+	ctx, sr := startSpan(t)
+	r := logxtest.NewRecorder()
+	l := New(ctx, logx.FilterLevel(r, logx.LevelFatal), Config{EventLevels: logx.LevelBeyondWarn})
+
+	calls := 0
+	factory := func() (string, []interface{}) {
+		calls++
+		return "fn", nil
+	}
+
+	// The events are recorded even if the wrapped Logger drops the messages.
+	l.Log(logx.LevelError, "e")
+	l.LogFn(logx.LevelWarn, factory)
+	l.LogFn(logx.LevelFatal, factory)
+	l.LogFn(logx.LevelInfo, factory)
+
+	a := assert.New(t)
+	a.Equal(2, calls)
+	a.Equal(1, len(r.Messages))
+
+	events := sr.Started()[0].(sdktrace.ReadWriteSpan).Events()
+	if a.Equal(3, len(events)) {
+		a.Equal(attribute.String(SeverityAttrKey, "ERROR"), events[0].Attributes[0])
+		a.Equal(attribute.String(SeverityAttrKey, "WARN"), events[1].Attributes[0])
+		a.Equal(attribute.String(SeverityAttrKey, "FATAL"), events[2].Attributes[0])
+	}
+}
+
+func TestNew_noSpan(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := New(context.Background(), r, Config{EventLevels: -1})
+	l.Log(logx.LevelError, "e", "k", 1)
+
+	assert.Nil(t, KeyValues(context.Background()))
+	assert.Equal(t, "ERROR e k=1\n", r.String())
+}
+
+func TestMiddleware(t *testing.T) {
+	ctx, sr := startSpan(t)
+	kv := KeyValues(ctx)
+
+	r := logxtest.NewRecorder()
+	l := logx.NewHandlerLogger("", logx.Chain(r, Middleware(Config{})))
+	l.Log(logx.LevelInfo, "no-context")
+	logx.WithContext(l, ctx).Log(logx.LevelError, "e")
+
+	a := assert.New(t)
+	a.Empty(r.Messages[0].KeyValues)
+	a.Equal([]interface{}{TraceIDKey, kv[1], SpanIDKey, kv[3]}, r.Messages[1].KeyValues)
+	a.Empty(sr.Started()[0].(sdktrace.ReadWriteSpan).Events())
+}
@@ -0,0 +1,51 @@
+package logxtest
+
+import (
+	"sync"
+
+	"github.com/cmstar/go-logx"
+)
+
+// ExitRecorder records the calls of an exit function, such as logx.OpConfig.Exit, without terminating the program.
+// It is safe for concurrent use.
+type ExitRecorder struct {
+	mu    sync.Mutex
+	codes []int
+}
+
+// Exit records the exit code. It can be used as logx.OpConfig.Exit.
+func (r *ExitRecorder) Exit(code int) {
+	r.mu.Lock()
+	r.codes = append(r.codes, code)
+	r.mu.Unlock()
+}
+
+// Codes returns the recorded exit codes.
+func (r *ExitRecorder) Codes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.codes...)
+}
+
+// Exited reports whether Exit() has been called.
+func (r *ExitRecorder) Exited() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.codes) > 0
+}
+
+// StubExit sets config.Exit to the Exit method of a new ExitRecorder, so that the LoggerOp created by
+// config.Op() records the calls of LoggerOp.FatalExit() and its variants, e.g.
+//
+//	var config logx.OpConfig
+//	exit := logxtest.StubExit(&config)
+//	config.Op(logger).FatalExit("bye")
+//	// exit.Codes() is [1]
+//
+// Nothing global is replaced, tests using it can run in parallel.
+// Note that the program is not terminated, the code after the calls of LoggerOp.FatalExit() continues to run.
+func StubExit(config *logx.OpConfig) *ExitRecorder {
+	r := new(ExitRecorder)
+	config.Exit = r.Exit
+	return r
+}
@@ -0,0 +1,26 @@
+package logxtest
+
+import (
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStubExit(t *testing.T) {
+	t.Parallel()
+
+	var config logx.OpConfig
+	exit := StubExit(&config)
+	assert.False(t, exit.Exited())
+
+	op := config.Op(NewRecorder())
+	op.FatalExit("a")
+	op.FatalExitkv("k", 1)
+	assert.True(t, exit.Exited())
+	assert.Equal(t, []int{1, 1}, exit.Codes())
+
+	// Another config is not affected.
+	other := StubExit(&logx.OpConfig{})
+	assert.False(t, other.Exited())
+}
@@ -26,6 +26,7 @@ type LogRecorder struct {
 }
 
 var _ logx.Logger = (*LogRecorder)(nil)
+var _ logx.Handler = (*LogRecorder)(nil)
 
 func (r *LogRecorder) Log(level logx.Level, message string, keyValues ...interface{}) error {
 	r.Messages = append(r.Messages, LogMessage{
@@ -41,6 +42,12 @@ func (r *LogRecorder) LogFn(level logx.Level, messageFactory func() (message str
 	return r.Log(level, m, k...)
 }
 
+// Handle implements logx.Handler, so the LogRecorder can be used as the sink of a logx.Handler pipeline.
+// Only the level, message and key-values of the entry are recorded.
+func (r *LogRecorder) Handle(entry logx.Entry) error {
+	return r.Log(entry.Level, entry.Message, entry.KeyValues...)
+}
+
 // Lines returns a slice of strings, each element is a formatted log message.
 // It formats log messages in the same manner of logx.StdLogger.
 func (r *LogRecorder) Lines() []string {
@@ -28,3 +28,9 @@ FATAL fatal-msg k3=v3
 `
 	a.Equal(wholeLog, r.String())
 }
+
+func TestLogRecorder_Handle(t *testing.T) {
+	r := NewRecorder()
+	r.Handle(logx.Entry{Level: logx.LevelInfo, Message: "m", KeyValues: []interface{}{"k", 1}, Name: "n"})
+	assert.Equal(t, "INFO m k=1\n", r.String())
+}
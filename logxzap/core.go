@@ -0,0 +1,81 @@
+package logxzap
+
+import (
+	"github.com/cmstar/go-logx"
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerNameKey is the key of the logger name, which is added to the key-values written by the Core,
+// if the zap entry has a logger name.
+const LoggerNameKey = "logger"
+
+// core is a zapcore.Core which writes log entries to a logx.Logger.
+type core struct {
+	logger  logx.Logger
+	enabler zapcore.LevelEnabler
+	fields  []interface{} // Key-values converted from the fields added by With().
+	prefix  string        // The key prefix of the namespaces opened by With(), see kvEncoder.
+}
+
+// NewCore creates a zapcore.Core which writes log entries to the given logx.Logger, thus a *zap.Logger can
+// be created with zap.New(NewCore(...)) to share the sinks of the logx.Logger.
+//
+// Levels are mapped with FromZapLevel(). Fields are converted to key-value pairs in order; the key-values of
+// fields added by With() come first. Namespaces are flattened, e.g. zap.Namespace("ns") followed by
+// zap.Int("k", 1) produces the key-value pair ns.k=1.
+// Sync() flushes the logx.Logger, see logx.Flush().
+// A level is enabled if it is enabled by both the enabler and the logx.Logger, see logx.Enabled().
+// If enabler is nil, all levels are enabled by the enabler.
+func NewCore(logger logx.Logger, enabler zapcore.LevelEnabler) zapcore.Core {
+	if enabler == nil {
+		enabler = zapcore.DebugLevel
+	}
+	return &core{
+		logger:  logger,
+		enabler: enabler,
+	}
+}
+
+func (c *core) Enabled(level zapcore.Level) bool {
+	return c.enabler.Enabled(level) && logx.Enabled(c.logger, FromZapLevel(level))
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	enc := &kvEncoder{keyValues: c.fields[:len(c.fields):len(c.fields)], prefix: c.prefix}
+	encodeFields(enc, fields)
+
+	cp := *c
+	cp.fields = enc.keyValues
+	cp.prefix = enc.prefix
+	return &cp
+}
+
+func (c *core) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	keyValues := make([]interface{}, 0, 2+len(c.fields)+len(fields)*2)
+	if entry.LoggerName != "" {
+		keyValues = append(keyValues, LoggerNameKey, entry.LoggerName)
+	}
+	keyValues = append(keyValues, c.fields...)
+
+	enc := &kvEncoder{keyValues: keyValues, prefix: c.prefix}
+	encodeFields(enc, fields)
+	return c.logger.Log(FromZapLevel(entry.Level), entry.Message, enc.keyValues...)
+}
+
+func (c *core) Sync() error {
+	return logx.Flush(c.logger)
+}
+
+// encodeFields adds the fields to the encoder in order.
+func encodeFields(enc *kvEncoder, fields []zapcore.Field) {
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+}
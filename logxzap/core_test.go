@@ -0,0 +1,80 @@
+package logxzap
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCore(t *testing.T) {
+	r := logxtest.NewRecorder()
+	z := zap.New(NewCore(r, zapcore.InfoLevel))
+
+	z.Debug("d")
+	z.Info("i", zap.Int("k", 1))
+	z.Named("n").With(zap.String("w", "x")).Warn("w", zap.Error(errors.New("e")))
+	z.Error("e")
+	z.DPanic("dp")
+
+	assert.Equal(t, `INFO i k=1
+WARN w logger=n w=x error=e
+ERROR e
+FATAL dp
+`, r.String())
+}
+
+func TestCore_nilEnabler(t *testing.T) {
+	r := logxtest.NewRecorder()
+	c := NewCore(r, nil)
+
+	assert.True(t, c.Enabled(zapcore.DebugLevel))
+	assert.NoError(t, c.Sync())
+
+	zap.New(c).Debug("d")
+	assert.Equal(t, logx.LevelDebug, r.Messages[0].Level)
+}
+
+func TestCore_fieldOrder(t *testing.T) {
+	r := logxtest.NewRecorder()
+	z := zap.New(NewCore(r, nil)).With(zap.String("w", "x"), zap.Namespace("ns"), zap.Int("a", 1))
+
+	z.Info("i",
+		zap.Int("z", 1), zap.String("y", "2"), zap.Bool("x", true),
+		zap.Inline(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddInt("m2", 2)
+			enc.AddInt("m1", 1)
+			return nil
+		})),
+		zap.Namespace("sub"), zap.Strings("s", []string{"a", "b"}),
+	)
+	z.Error("e", zap.Error(fmt.Errorf("wrap: %w", errors.New("e"))), zap.Int("b", 2))
+
+	assert.Equal(t, `INFO i w=x ns.a=1 ns.z=1 ns.y=2 ns.x=true ns.m2=2 ns.m1=1 ns.sub.s=[a b]
+ERROR e w=x ns.a=1 ns.error=wrap: e ns.b=2
+`, r.String())
+}
+
+func TestCore_Sync(t *testing.T) {
+	flushed := 0
+	c := NewCore(flushLogger{logxtest.NewRecorder(), &flushed}, nil)
+
+	assert.NoError(t, c.Sync())
+	assert.Equal(t, 1, flushed)
+}
+
+// flushLogger counts the calls of Flush.
+type flushLogger struct {
+	logx.Logger
+	flushed *int
+}
+
+func (l flushLogger) Flush() error {
+	*l.flushed++
+	return nil
+}
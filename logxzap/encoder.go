@@ -0,0 +1,72 @@
+package logxzap
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// kvEncoder is a zapcore.ObjectEncoder which appends the encoded fields to key-value pairs in the order they
+// are added. Values are the same as the ones produced by zapcore.MapObjectEncoder.
+//
+// The key-value pairs are flat, zap.Namespace() is flattened by prefixing the keys of the subsequent fields
+// with the namespace and a dot.
+type kvEncoder struct {
+	keyValues []interface{}
+	prefix    string // The prefix of keys, empty or ends with a dot.
+}
+
+// add appends a key-value pair.
+func (e *kvEncoder) add(key string, value interface{}) {
+	e.keyValues = append(e.keyValues, e.prefix+key, value)
+}
+
+// addMap appends the value added to a zapcore.MapObjectEncoder by the given function, it is used for the
+// values whose encoders are not exported by zap.
+func (e *kvEncoder) addMap(key string, fn func(m *zapcore.MapObjectEncoder) error) error {
+	m := zapcore.NewMapObjectEncoder()
+	err := fn(m)
+	if v, ok := m.Fields[key]; ok {
+		e.add(key, v)
+	}
+	return err
+}
+
+func (e *kvEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	return e.addMap(key, func(m *zapcore.MapObjectEncoder) error { return m.AddArray(key, marshaler) })
+}
+
+func (e *kvEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	return e.addMap(key, func(m *zapcore.MapObjectEncoder) error { return m.AddObject(key, marshaler) })
+}
+
+func (e *kvEncoder) AddReflected(key string, value interface{}) error {
+	e.add(key, value)
+	return nil
+}
+
+func (e *kvEncoder) OpenNamespace(key string) {
+	e.prefix += key + "."
+}
+
+func (e *kvEncoder) AddBinary(key string, value []byte)          { e.add(key, value) }
+func (e *kvEncoder) AddByteString(key string, value []byte)      { e.add(key, string(value)) }
+func (e *kvEncoder) AddBool(key string, value bool)              { e.add(key, value) }
+func (e *kvEncoder) AddComplex128(key string, value complex128)  { e.add(key, value) }
+func (e *kvEncoder) AddComplex64(key string, value complex64)    { e.add(key, value) }
+func (e *kvEncoder) AddDuration(key string, value time.Duration) { e.add(key, value) }
+func (e *kvEncoder) AddFloat64(key string, value float64)        { e.add(key, value) }
+func (e *kvEncoder) AddFloat32(key string, value float32)        { e.add(key, value) }
+func (e *kvEncoder) AddInt(key string, value int)                { e.add(key, value) }
+func (e *kvEncoder) AddInt64(key string, value int64)            { e.add(key, value) }
+func (e *kvEncoder) AddInt32(key string, value int32)            { e.add(key, value) }
+func (e *kvEncoder) AddInt16(key string, value int16)            { e.add(key, value) }
+func (e *kvEncoder) AddInt8(key string, value int8)              { e.add(key, value) }
+func (e *kvEncoder) AddString(key, value string)                 { e.add(key, value) }
+func (e *kvEncoder) AddTime(key string, value time.Time)         { e.add(key, value) }
+func (e *kvEncoder) AddUint(key string, value uint)              { e.add(key, value) }
+func (e *kvEncoder) AddUint64(key string, value uint64)          { e.add(key, value) }
+func (e *kvEncoder) AddUint32(key string, value uint32)          { e.add(key, value) }
+func (e *kvEncoder) AddUint16(key string, value uint16)          { e.add(key, value) }
+func (e *kvEncoder) AddUint8(key string, value uint8)            { e.add(key, value) }
+func (e *kvEncoder) AddUintptr(key string, value uintptr)        { e.add(key, value) }
@@ -0,0 +1,143 @@
+// Package logxzap adapts go.uber.org/zap to the logx package.
+//
+// New() creates a logx.Logger which writes log messages to a *zap.Logger;
+// NewCore() creates a zapcore.Core which writes log entries to a logx.Logger.
+package logxzap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger is a logx.Logger which writes log messages to a *zap.Logger.
+type Logger struct {
+	z *zap.Logger
+}
+
+var _ logx.Logger = (*Logger)(nil)
+
+// New creates a new Logger which writes log messages to the given *zap.Logger.
+//
+// Levels are mapped with ToZapLevel(). Key-value pairs are mapped to zap.Any() fields,
+// the keys are formatted with fmt.Sprint(); an unpaired value is mapped to a field with key 'UNKNOWN'.
+//
+// Messages at logx.LevelFatal are written at zapcore.FatalLevel, but unlike zap.Logger.Fatal(),
+// the process is not terminated.
+//
+// The caller reported by zap is the function calling the Logger, which may be inside the logx package,
+// use zap.AddCallerSkip() on the *zap.Logger to adjust it.
+func New(logger *zap.Logger) *Logger {
+	return &Logger{logger}
+}
+
+// Zap returns the underlying *zap.Logger.
+func (l *Logger) Zap() *zap.Logger {
+	return l.z
+}
+
+// Log implements logx.Logger.Log().
+func (l *Logger) Log(level logx.Level, message string, keyValues ...interface{}) error {
+	zl := ToZapLevel(level)
+
+	if zl == zapcore.FatalLevel {
+		// zap.Logger.Check() registers a hook terminating the process for the fatal level,
+		// write to the core directly to avoid that.
+		entry := zapcore.Entry{
+			LoggerName: l.z.Name(),
+			Time:       time.Now(),
+			Level:      zl,
+			Message:    message,
+		}
+
+		if ce := l.z.Core().Check(entry, nil); ce != nil {
+			ce.Write(toFields(keyValues)...)
+		}
+		return nil
+	}
+
+	if ce := l.z.Check(zl, message); ce != nil {
+		ce.Write(toFields(keyValues)...)
+	}
+	return nil
+}
+
+// LogFn implements logx.Logger.LogFn().
+// The messageFactory is invoked only if the level is enabled on the core of the *zap.Logger.
+func (l *Logger) LogFn(level logx.Level, messageFactory func() (string, []interface{})) error {
+	if !l.Enabled(level) {
+		return nil
+	}
+
+	message, keyValues := messageFactory()
+	return l.Log(level, message, keyValues...)
+}
+
+// Enabled implements logx.LevelEnabler.Enabled(), it checks the level with the core of the *zap.Logger.
+func (l *Logger) Enabled(level logx.Level) bool {
+	return l.z.Core().Enabled(ToZapLevel(level))
+}
+
+// ToZapLevel maps a logx.Level to a zapcore.Level.
+// logx.LevelTrace is mapped to zapcore.DebugLevel, since zap has no trace level.
+// If the given level is a combined level, the highest one is used.
+// Unknown levels are mapped to zapcore.InfoLevel.
+func ToZapLevel(level logx.Level) zapcore.Level {
+	switch {
+	case level&logx.LevelFatal != 0:
+		return zapcore.FatalLevel
+	case level&logx.LevelError != 0:
+		return zapcore.ErrorLevel
+	case level&logx.LevelWarn != 0:
+		return zapcore.WarnLevel
+	case level&logx.LevelInfo != 0:
+		return zapcore.InfoLevel
+	case level&(logx.LevelDebug|logx.LevelTrace) != 0:
+		return zapcore.DebugLevel
+	}
+	return zapcore.InfoLevel
+}
+
+// FromZapLevel maps a zapcore.Level to a logx.Level.
+// zapcore.DPanicLevel, zapcore.PanicLevel and zapcore.FatalLevel are mapped to logx.LevelFatal.
+func FromZapLevel(level zapcore.Level) logx.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return logx.LevelDebug
+	case level == zapcore.InfoLevel:
+		return logx.LevelInfo
+	case level == zapcore.WarnLevel:
+		return logx.LevelWarn
+	case level == zapcore.ErrorLevel:
+		return logx.LevelError
+	}
+	return logx.LevelFatal
+}
+
+// toFields converts key-value pairs to zap fields.
+func toFields(keyValues []interface{}) []zap.Field {
+	length := len(keyValues)
+	if length == 0 {
+		return nil
+	}
+
+	fields := make([]zap.Field, 0, (length+1)/2)
+	for i := 0; i < length-1; i += 2 {
+		fields = append(fields, zap.Any(toKey(keyValues[i]), keyValues[i+1]))
+	}
+
+	if length%2 != 0 {
+		fields = append(fields, zap.Any("UNKNOWN", keyValues[length-1]))
+	}
+	return fields
+}
+
+func toKey(k interface{}) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+	return fmt.Sprint(k)
+}
@@ -0,0 +1,69 @@
+package logxzap
+
+import (
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogger(t *testing.T) {
+	obs, logs := observer.New(zapcore.InfoLevel)
+	l := New(zap.New(obs).Named("n"))
+
+	l.Log(logx.LevelDebug, "d")
+	l.Log(logx.LevelInfo, "i", "k1", 1, 2, "v2", "v3")
+	l.Log(logx.LevelWarn, "w")
+	l.Log(logx.LevelError, "e")
+	l.Log(logx.LevelFatal, "f", "k", "v") // Must not exit.
+	l.LogFn(logx.LevelDebug, func() (string, []interface{}) {
+		t.Fatal("the factory should not be invoked")
+		return "", nil
+	})
+	l.LogFn(logx.LevelInfo, func() (string, []interface{}) { return "i2", nil })
+
+	a := assert.New(t)
+	entries := logs.AllUntimed()
+	a.Equal(5, len(entries))
+
+	a.Equal(zapcore.InfoLevel, entries[0].Level)
+	a.Equal("i", entries[0].Message)
+	a.Equal("n", entries[0].LoggerName)
+	a.Equal(map[string]interface{}{"k1": int64(1), "2": "v2", "UNKNOWN": "v3"}, entries[0].ContextMap())
+
+	a.Equal(zapcore.WarnLevel, entries[1].Level)
+	a.Equal(zapcore.ErrorLevel, entries[2].Level)
+
+	a.Equal(zapcore.FatalLevel, entries[3].Level)
+	a.Equal("f", entries[3].Message)
+	a.Equal("n", entries[3].LoggerName)
+	a.Equal(map[string]interface{}{"k": "v"}, entries[3].ContextMap())
+
+	a.Equal("i2", entries[4].Message)
+
+	a.False(logx.Enabled(l, logx.LevelDebug))
+	a.True(logx.Enabled(l, logx.LevelInfo))
+}
+
+func TestLevelMapping(t *testing.T) {
+	a := assert.New(t)
+	a.Equal(zapcore.DebugLevel, ToZapLevel(logx.LevelTrace))
+	a.Equal(zapcore.DebugLevel, ToZapLevel(logx.LevelDebug))
+	a.Equal(zapcore.InfoLevel, ToZapLevel(logx.LevelInfo))
+	a.Equal(zapcore.WarnLevel, ToZapLevel(logx.LevelWarn))
+	a.Equal(zapcore.ErrorLevel, ToZapLevel(logx.LevelError))
+	a.Equal(zapcore.FatalLevel, ToZapLevel(logx.LevelFatal))
+	a.Equal(zapcore.ErrorLevel, ToZapLevel(logx.LevelDebug|logx.LevelError))
+	a.Equal(zapcore.InfoLevel, ToZapLevel(0))
+
+	a.Equal(logx.LevelDebug, FromZapLevel(zapcore.DebugLevel))
+	a.Equal(logx.LevelInfo, FromZapLevel(zapcore.InfoLevel))
+	a.Equal(logx.LevelWarn, FromZapLevel(zapcore.WarnLevel))
+	a.Equal(logx.LevelError, FromZapLevel(zapcore.ErrorLevel))
+	a.Equal(logx.LevelFatal, FromZapLevel(zapcore.DPanicLevel))
+	a.Equal(logx.LevelFatal, FromZapLevel(zapcore.PanicLevel))
+	a.Equal(logx.LevelFatal, FromZapLevel(zapcore.FatalLevel))
+}
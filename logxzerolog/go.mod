@@ -0,0 +1,9 @@
+module github.com/cmstar/go-logx/logxzerolog
+
+go 1.16
+
+require (
+	github.com/cmstar/go-logx v1.3.0
+	github.com/rs/zerolog v1.33.0
+	github.com/stretchr/testify v1.7.0
+)
@@ -0,0 +1,155 @@
+// Package logxzerolog adapts github.com/rs/zerolog to the logx package.
+package logxzerolog
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"github.com/rs/zerolog"
+)
+
+// Logger is a logx.Logger which writes log messages to a zerolog.Logger.
+type Logger struct {
+	z zerolog.Logger
+}
+
+var _ logx.Logger = (*Logger)(nil)
+
+// New creates a new Logger which writes log messages to the given zerolog.Logger.
+//
+// Levels are mapped with ToZerologLevel(). Messages at logx.LevelFatal are written at zerolog.FatalLevel,
+// but the process is not terminated.
+//
+// Key-value pairs are added to the event with typed methods when the type of the value is known,
+// e.g. strings with Event.Str(), errors with Event.AnErr(), durations with Event.Dur(), which avoid
+// the reflection used by Event.Interface(). The keys are formatted with fmt.Sprint();
+// an unpaired value is added with key 'UNKNOWN'.
+func New(logger zerolog.Logger) *Logger {
+	return &Logger{logger}
+}
+
+// Zerolog returns the underlying zerolog.Logger.
+func (l *Logger) Zerolog() zerolog.Logger {
+	return l.z
+}
+
+// Log implements logx.Logger.Log().
+func (l *Logger) Log(level logx.Level, message string, keyValues ...interface{}) error {
+	ev := l.z.WithLevel(ToZerologLevel(level))
+	if ev == nil {
+		return nil
+	}
+
+	send(ev, message, keyValues)
+	return nil
+}
+
+// LogFn implements logx.Logger.LogFn().
+// The messageFactory is invoked only if the level is enabled, which depends on the level and
+// the sampler of the zerolog.Logger, and zerolog.GlobalLevel().
+func (l *Logger) LogFn(level logx.Level, messageFactory func() (string, []interface{})) error {
+	ev := l.z.WithLevel(ToZerologLevel(level))
+	if ev == nil {
+		return nil
+	}
+
+	message, keyValues := messageFactory()
+	send(ev, message, keyValues)
+	return nil
+}
+
+// Enabled implements logx.LevelEnabler.Enabled(), it checks the level with the level of the zerolog.Logger
+// and zerolog.GlobalLevel(). The sampler of the zerolog.Logger is not considered.
+func (l *Logger) Enabled(level logx.Level) bool {
+	zl := ToZerologLevel(level)
+	return zl >= l.z.GetLevel() && zl >= zerolog.GlobalLevel()
+}
+
+// ToZerologLevel maps a logx.Level to a zerolog.Level.
+// If the given level is a combined level, the highest one is used.
+// Unknown levels are mapped to zerolog.InfoLevel.
+func ToZerologLevel(level logx.Level) zerolog.Level {
+	switch {
+	case level&logx.LevelFatal != 0:
+		return zerolog.FatalLevel
+	case level&logx.LevelError != 0:
+		return zerolog.ErrorLevel
+	case level&logx.LevelWarn != 0:
+		return zerolog.WarnLevel
+	case level&logx.LevelInfo != 0:
+		return zerolog.InfoLevel
+	case level&logx.LevelDebug != 0:
+		return zerolog.DebugLevel
+	case level&logx.LevelTrace != 0:
+		return zerolog.TraceLevel
+	}
+	return zerolog.InfoLevel
+}
+
+// send adds the key-value pairs to the event, then sends it with the message.
+func send(ev *zerolog.Event, message string, keyValues []interface{}) {
+	length := len(keyValues)
+	for i := 0; i < length-1; i += 2 {
+		addField(ev, toKey(keyValues[i]), keyValues[i+1])
+	}
+
+	if length%2 != 0 {
+		addField(ev, "UNKNOWN", keyValues[length-1])
+	}
+
+	ev.Msg(message)
+}
+
+// addField adds a key-value pair to the event, using the typed method if possible.
+func addField(ev *zerolog.Event, key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		ev.Str(key, v)
+	case int:
+		ev.Int(key, v)
+	case int64:
+		ev.Int64(key, v)
+	case int32:
+		ev.Int32(key, v)
+	case int16:
+		ev.Int16(key, v)
+	case int8:
+		ev.Int8(key, v)
+	case uint:
+		ev.Uint(key, v)
+	case uint64:
+		ev.Uint64(key, v)
+	case uint32:
+		ev.Uint32(key, v)
+	case uint16:
+		ev.Uint16(key, v)
+	case uint8:
+		ev.Uint8(key, v)
+	case float64:
+		ev.Float64(key, v)
+	case float32:
+		ev.Float32(key, v)
+	case bool:
+		ev.Bool(key, v)
+	case time.Duration:
+		ev.Dur(key, v)
+	case time.Time:
+		ev.Time(key, v)
+	case []byte:
+		ev.Bytes(key, v)
+	case error:
+		ev.AnErr(key, v)
+	case fmt.Stringer:
+		ev.Stringer(key, v)
+	default:
+		ev.Interface(key, v)
+	}
+}
+
+func toKey(k interface{}) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+	return fmt.Sprint(k)
+}
@@ -0,0 +1,118 @@
+package logxzerolog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type stringer struct{}
+
+func (stringer) String() string { return "str" }
+
+func TestLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := New(zerolog.New(buf).Level(zerolog.InfoLevel))
+
+	l.Log(logx.LevelDebug, "d")
+	l.Log(logx.LevelInfo, "i", "s", "v", "i", 1, "i64", int64(2), "u8", uint8(3), "f", 1.5, "b", true)
+	l.Log(logx.LevelWarn, "w",
+		"d", 2*time.Second, "t", time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		"e", errors.New("err"), "st", stringer{}, "m", map[string]int{"a": 1}, 10, "x", "odd")
+	l.Log(logx.LevelFatal, "f") // Must not exit.
+	l.LogFn(logx.LevelDebug, func() (string, []interface{}) {
+		t.Fatal("the factory should not be invoked")
+		return "", nil
+	})
+	l.LogFn(logx.LevelError, func() (string, []interface{}) { return "e", []interface{}{"k", "v"} })
+
+	assert.Equal(t, `{"level":"info","s":"v","i":1,"i64":2,"u8":3,"f":1.5,"b":true,"message":"i"}
+{"level":"warn","d":2000,"t":"2021-01-02T03:04:05Z","e":"err","st":"str","m":{"a":1},"10":"x","UNKNOWN":"odd","message":"w"}
+{"level":"fatal","message":"f"}
+{"level":"error","k":"v","message":"e"}
+`, buf.String())
+}
+
+func TestLogger_globalLevel(t *testing.T) {
+	defer zerolog.SetGlobalLevel(zerolog.GlobalLevel())
+	zerolog.SetGlobalLevel(zerolog.ErrorLevel)
+
+	buf := new(bytes.Buffer)
+	l := New(zerolog.New(buf))
+	l.LogFn(logx.LevelWarn, func() (string, []interface{}) {
+		t.Fatal("the factory should not be invoked")
+		return "", nil
+	})
+	l.Log(logx.LevelError, "e")
+
+	assert.Equal(t, `{"level":"error","message":"e"}`+"\n", buf.String())
+	assert.False(t, logx.Enabled(l, logx.LevelWarn))
+	assert.True(t, logx.Enabled(l, logx.LevelError))
+}
+
+func TestToZerologLevel(t *testing.T) {
+	a := assert.New(t)
+	a.Equal(zerolog.TraceLevel, ToZerologLevel(logx.LevelTrace))
+	a.Equal(zerolog.DebugLevel, ToZerologLevel(logx.LevelDebug))
+	a.Equal(zerolog.InfoLevel, ToZerologLevel(logx.LevelInfo))
+	a.Equal(zerolog.WarnLevel, ToZerologLevel(logx.LevelWarn))
+	a.Equal(zerolog.ErrorLevel, ToZerologLevel(logx.LevelError))
+	a.Equal(zerolog.FatalLevel, ToZerologLevel(logx.LevelFatal))
+	a.Equal(zerolog.ErrorLevel, ToZerologLevel(logx.LevelDebug|logx.LevelError))
+	a.Equal(zerolog.InfoLevel, ToZerologLevel(0))
+}
+
+// The following benchmarks compare the overhead of logx with using zerolog directly.
+
+func BenchmarkZerolog(b *testing.B) {
+	z := zerolog.New(io.Discard)
+	err := errors.New("err")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		z.Info().Str("s", "v").Int("i", i).Dur("d", time.Second).AnErr("e", err).Msg("message")
+	}
+}
+
+func BenchmarkLogger(b *testing.B) {
+	l := New(zerolog.New(io.Discard))
+	err := errors.New("err")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Log(logx.LevelInfo, "message", "s", "v", "i", i, "d", time.Second, "e", err)
+	}
+}
+
+func BenchmarkLoggerOp(b *testing.B) {
+	op := logx.Op(New(zerolog.New(io.Discard)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		op.Infof("message %d", i)
+	}
+}
+
+func BenchmarkZerolog_disabled(b *testing.B) {
+	z := zerolog.New(io.Discard).Level(zerolog.ErrorLevel)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		z.Info().Str("s", "v").Int("i", i).Msg("message")
+	}
+}
+
+func BenchmarkLogger_disabled(b *testing.B) {
+	l := New(zerolog.New(io.Discard).Level(zerolog.ErrorLevel))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Log(logx.LevelInfo, "message", "s", "v", "i", i)
+	}
+}
@@ -0,0 +1,62 @@
+package logx
+
+import (
+	"errors"
+	"strings"
+)
+
+// multiError combines several errors, it is returned by joinErrors().
+type multiError []error
+
+func (e multiError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors, in the same manner of the errors joined by errors.Join() since Go 1.20.
+func (e multiError) Unwrap() []error {
+	return e
+}
+
+// Is reports whether any of the errors matches the target, it makes errors.Is() work.
+func (e multiError) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error matching the target, it makes errors.As() work.
+func (e multiError) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinErrors returns an error combining the non-nil errors.
+// Returns nil if there is none, the error itself if there is only one.
+func joinErrors(errs ...error) error {
+	var res multiError
+	for _, err := range errs {
+		if err != nil {
+			res = append(res, err)
+		}
+	}
+
+	switch len(res) {
+	case 0:
+		return nil
+	case 1:
+		return res[0]
+	default:
+		return res
+	}
+}
@@ -0,0 +1,29 @@
+package logx
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinErrors(t *testing.T) {
+	a := assert.New(t)
+	a.Nil(joinErrors())
+	a.Nil(joinErrors(nil, nil))
+
+	e1 := errors.New("e1")
+	a.Same(e1, joinErrors(nil, e1))
+
+	pathErr := &os.PathError{Op: "open", Path: "p", Err: os.ErrNotExist}
+	err := joinErrors(e1, nil, pathErr)
+	a.EqualError(err, "e1; open p: file does not exist")
+	a.True(errors.Is(err, e1))
+	a.True(errors.Is(err, os.ErrNotExist))
+	a.False(errors.Is(err, os.ErrExist))
+
+	var target *os.PathError
+	a.True(errors.As(err, &target))
+	a.Same(pathErr, target)
+}
@@ -1,6 +1,7 @@
 package logx
 
 // NopLogger is a no-op logger, the Log function do nothing and returns no error.
+// It implements LevelEnabler, all levels are disabled.
 // It is safe for concurrent use.
 var NopLogger Logger = new(nopLogger)
 
@@ -13,3 +14,7 @@ func (logger *nopLogger) Log(level Level, message string, keyValues ...interface
 func (logger *nopLogger) LogFn(level Level, messageFactory func() (string, []interface{})) error {
 	return nil
 }
+
+func (logger *nopLogger) Enabled(level Level) bool {
+	return false
+}
@@ -0,0 +1,337 @@
+package logx
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimitSummaryMessage is the message of the summary line written by the Logger created by RateLimit,
+// when some messages were suppressed and the limiting ends.
+const RateLimitSummaryMessage = "rate limit: messages suppressed"
+
+// defaultRateLimitMaxBuckets is the default value of RateLimitConfig.MaxBuckets.
+const defaultRateLimitMaxBuckets = 1000
+
+// RateLimitConfig configures the Logger created by RateLimit.
+type RateLimitConfig struct {
+	// Rate is the number of messages allowed per second for each bucket.
+	// If it is not positive, no message is limited.
+	Rate float64
+
+	// Burst is the maximum number of messages that can be logged at once, that is, the size of the token bucket.
+	// If it is less than 1, 1 is used.
+	Burst int
+
+	// Levels is the mask of levels to be limited. Messages at other levels are always logged.
+	// If it is 0, all levels are limited.
+	Levels Level
+
+	// ByMessage, if true, gives each distinct message of a level its own bucket,
+	// instead of sharing the bucket of the level.
+	ByMessage bool
+
+	// ByKey, if not empty, gives each distinct value of the given key in keyValues its own bucket,
+	// instead of sharing the bucket of the level. Messages without the key use the bucket of the level.
+	// It is ignored if ByMessage is true.
+	ByKey string
+
+	// MaxBuckets limits the number of buckets kept for ByMessage and ByKey. The least recently used bucket
+	// is evicted when the limit is exceeded, the summary line of its suppressed messages is written at that time.
+	// If it is not positive, 1000 is used.
+	MaxBuckets int
+
+	// Now returns the current time. If it is nil, time.Now is used.
+	// It is useful for testing.
+	Now func() time.Time
+}
+
+// RateLimit wraps the given Logger, returns a new Logger which limits the throughput of log messages
+// with token buckets. Each level has its own bucket; the buckets are refilled at config.Rate tokens per second,
+// and each message takes one token. A message is dropped if there is no token left.
+//
+// When the limiting of a bucket ends, that is, the bucket has a token again, a summary line is written
+// at the same level, with the message RateLimitSummaryMessage and a key-value pair suppressed=N.
+// There is no background timer, the buckets are checked on each call of Log() or LogFn(), whichever bucket
+// the message belongs to. The returned Logger implements Flusher, Flush() writes the summary lines of all
+// pending suppressions, so that a burst after which nothing is logged is reported too; call it periodically
+// or before exiting, see also logx.Flush().
+//
+// The LogFn factory is never invoked for suppressed messages when the messages are limited by the buckets
+// of levels. With ByMessage or ByKey, the bucket is decided by the message or the key-values, which are built
+// by the factory, so the factory of LogFn() is invoked, if the level is enabled on the given Logger,
+// before the bucket is checked; use Log() where building the message is cheap, or limit by levels.
+func RateLimit(raw Logger, config RateLimitConfig) Logger {
+	if config.Burst < 1 {
+		config.Burst = 1
+	}
+	if config.Levels == 0 {
+		config.Levels = -1
+	}
+	if config.MaxBuckets <= 0 {
+		config.MaxBuckets = defaultRateLimitMaxBuckets
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &rateLimiter{
+		logger:  raw,
+		config:  config,
+		levels:  make(map[Level]*tokenBucket),
+		buckets: make(map[rateLimitKey]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// rateLimiter is a Logger which limits log messages with token buckets.
+type rateLimiter struct {
+	logger Logger
+	config RateLimitConfig
+
+	mu          sync.Mutex
+	levels      map[Level]*tokenBucket         // The buckets of each level.
+	buckets     map[rateLimitKey]*list.Element // Maps keys to the elements of lru, used by ByMessage and ByKey.
+	lru         *list.List                     // Elements are *tokenBucket, the most recently used is at the front.
+	suppressing []*tokenBucket                 // The buckets having suppressed messages, in the order of suppression.
+}
+
+// rateLimitKey identifies a bucket used by ByMessage and ByKey.
+type rateLimitKey struct {
+	level Level
+	value string // The message or the value of the key.
+}
+
+// tokenBucket is a simple token bucket, it is not safe for concurrent use.
+type tokenBucket struct {
+	level      Level
+	key        *rateLimitKey // The key of the bucket, nil for the bucket of a level.
+	tokens     float64
+	last       time.Time
+	suppressed int // The number of messages suppressed since the limiting starts.
+}
+
+// refill adds tokens according to the time elapsed since the last refilling.
+func (b *tokenBucket) refill(now time.Time, rate float64, burst int) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	b.last = now
+
+	b.tokens += elapsed * rate
+	if b.tokens > float64(burst) {
+		b.tokens = float64(burst)
+	}
+}
+
+// rateLimitSummary is a summary line to be written.
+type rateLimitSummary struct {
+	level      Level
+	key        *rateLimitKey
+	suppressed int
+}
+
+// takeSummary appends the summary of the bucket to summaries and resets the count, if it has suppressed messages.
+func (b *tokenBucket) takeSummary(summaries []rateLimitSummary) []rateLimitSummary {
+	if b.suppressed == 0 {
+		return summaries
+	}
+
+	summaries = append(summaries, rateLimitSummary{b.level, b.key, b.suppressed})
+	b.suppressed = 0
+	return summaries
+}
+
+func (l *rateLimiter) Log(level Level, message string, keyValues ...interface{}) error {
+	if !l.limited(level) {
+		return l.logger.Log(level, message, keyValues...)
+	}
+
+	return l.log(level, func() (string, []interface{}) { return message, keyValues })
+}
+
+func (l *rateLimiter) LogFn(level Level, messageFactory func() (message string, keyValues []interface{})) error {
+	if !l.limited(level) {
+		return l.logger.LogFn(level, messageFactory)
+	}
+
+	return l.log(level, messageFactory)
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (l *rateLimiter) Unwrap() Logger {
+	return l.logger
+}
+
+// Enabled implements LevelEnabler.Enabled(), the wrapped Logger decides.
+func (l *rateLimiter) Enabled(level Level) bool {
+	return Enabled(l.logger, level)
+}
+
+// limited reports whether messages at the given level should be limited.
+func (l *rateLimiter) limited(level Level) bool {
+	return l.config.Rate > 0 && (l.config.Levels&level) == level
+}
+
+func (l *rateLimiter) log(level Level, messageFactory func() (string, []interface{})) error {
+	now := l.config.Now()
+
+	// The factory is invoked at most once, evaluated tells whether message and keyValues are ready.
+	var message string
+	var keyValues []interface{}
+	var key *rateLimitKey
+	evaluated := false
+	if l.config.ByMessage || l.config.ByKey != "" {
+		if !Enabled(l.logger, level) {
+			return nil
+		}
+
+		message, keyValues = messageFactory()
+		evaluated = true
+		key = l.bucketKey(level, message, keyValues)
+	}
+
+	l.mu.Lock()
+	summaries := l.endLimiting(now, nil)
+
+	var b *tokenBucket
+	if key == nil {
+		b = l.levelBucket(level, now)
+	} else {
+		b, summaries = l.bucket(*key, now, summaries)
+	}
+
+	pass := b.tokens >= 1
+	if pass {
+		b.tokens--
+	} else {
+		if b.suppressed == 0 {
+			l.suppressing = append(l.suppressing, b)
+		}
+		b.suppressed++
+	}
+	l.mu.Unlock()
+
+	// The token is spent, the message is logged even if the summary lines fail.
+	summaryErr := l.logSummaries(summaries)
+	if !pass {
+		return summaryErr
+	}
+
+	var err error
+	if evaluated {
+		err = l.logger.Log(level, message, keyValues...)
+	} else {
+		err = l.logger.LogFn(level, messageFactory)
+	}
+	return joinErrors(summaryErr, err)
+}
+
+// endLimiting refills the buckets having suppressed messages, the limiting of a bucket ends if it has a token,
+// its summary is appended to summaries. The lock must be held.
+func (l *rateLimiter) endLimiting(now time.Time, summaries []rateLimitSummary) []rateLimitSummary {
+	remaining := l.suppressing[:0]
+	for _, b := range l.suppressing {
+		b.refill(now, l.config.Rate, l.config.Burst)
+		if b.tokens >= 1 {
+			summaries = b.takeSummary(summaries)
+		}
+
+		// The count may also be reset on eviction.
+		if b.suppressed > 0 {
+			remaining = append(remaining, b)
+		}
+	}
+
+	for i := len(remaining); i < len(l.suppressing); i++ {
+		l.suppressing[i] = nil
+	}
+	l.suppressing = remaining
+	return summaries
+}
+
+// Flush implements Flusher.Flush(), it logs the summary lines of all buckets which have suppressed messages,
+// whether the limiting ends or not.
+func (l *rateLimiter) Flush() error {
+	var summaries []rateLimitSummary
+
+	l.mu.Lock()
+	for _, b := range l.suppressing {
+		summaries = b.takeSummary(summaries)
+	}
+	l.suppressing = nil
+	l.mu.Unlock()
+
+	return l.logSummaries(summaries)
+}
+
+// logSummaries logs the summary lines, returns the errors combined.
+func (l *rateLimiter) logSummaries(summaries []rateLimitSummary) error {
+	var errs []error
+	for _, s := range summaries {
+		var kv []interface{}
+		if s.key != nil {
+			kv = l.summaryKeyValues(*s.key)
+		}
+		kv = append(kv, "suppressed", s.suppressed)
+		errs = append(errs, l.logger.Log(s.level, RateLimitSummaryMessage, kv...))
+	}
+	return joinErrors(errs...)
+}
+
+// levelBucket returns the refilled bucket of the given level. The lock must be held.
+func (l *rateLimiter) levelBucket(level Level, now time.Time) *tokenBucket {
+	b, ok := l.levels[level]
+	if !ok {
+		b = &tokenBucket{level: level, tokens: float64(l.config.Burst), last: now}
+		l.levels[level] = b
+		return b
+	}
+
+	b.refill(now, l.config.Rate, l.config.Burst)
+	return b
+}
+
+// bucket returns the refilled bucket of the given key. If there are too many buckets, the least recently used
+// one is evicted, its pending summary is appended to summaries. The lock must be held.
+func (l *rateLimiter) bucket(key rateLimitKey, now time.Time, summaries []rateLimitSummary) (*tokenBucket, []rateLimitSummary) {
+	if elem, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(elem)
+		b := elem.Value.(*tokenBucket)
+		b.refill(now, l.config.Rate, l.config.Burst)
+		return b, summaries
+	}
+
+	for l.lru.Len() >= l.config.MaxBuckets {
+		oldest := l.lru.Remove(l.lru.Back()).(*tokenBucket)
+		delete(l.buckets, *oldest.key)
+		summaries = oldest.takeSummary(summaries)
+	}
+
+	b := &tokenBucket{level: key.level, key: &key, tokens: float64(l.config.Burst), last: now}
+	l.buckets[key] = l.lru.PushFront(b)
+	return b, summaries
+}
+
+// bucketKey returns the key of the bucket for ByMessage or ByKey, nil if the message is limited only by its level.
+func (l *rateLimiter) bucketKey(level Level, message string, keyValues []interface{}) *rateLimitKey {
+	if l.config.ByMessage {
+		return &rateLimitKey{level, message}
+	}
+
+	for i := 0; i < len(keyValues)-1; i += 2 {
+		if fmt.Sprint(keyValues[i]) == l.config.ByKey {
+			return &rateLimitKey{level, fmt.Sprint(keyValues[i+1])}
+		}
+	}
+	return nil
+}
+
+// summaryKeyValues returns the key-values which identify the bucket in a summary line.
+func (l *rateLimiter) summaryKeyValues(key rateLimitKey) []interface{} {
+	if l.config.ByMessage {
+		return []interface{}{"message", key.value}
+	}
+	return []interface{}{l.config.ByKey, key.value}
+}
@@ -0,0 +1,209 @@
+package logx_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := logxtest.NewRecorder()
+	l := logx.RateLimit(r, logx.RateLimitConfig{
+		Rate:   1,
+		Burst:  2,
+		Levels: logx.LevelBeyondWarn,
+		Now:    func() time.Time { return now },
+	})
+
+	factoryCalls := 0
+	factory := func() (string, []interface{}) {
+		factoryCalls++
+		return "fn", nil
+	}
+
+	l.Log(logx.LevelError, "e1")
+	l.Log(logx.LevelError, "e2")
+	l.Log(logx.LevelError, "e3")
+	l.LogFn(logx.LevelError, factory)
+	l.Log(logx.LevelWarn, "w1")
+	l.Log(logx.LevelDebug, "d1")
+	l.Log(logx.LevelDebug, "d2")
+	l.Log(logx.LevelDebug, "d3")
+
+	now = now.Add(time.Second)
+	l.LogFn(logx.LevelError, factory)
+
+	a := assert.New(t)
+	a.Equal(1, factoryCalls)
+	a.Equal(`ERROR e1
+ERROR e2
+WARN w1
+DEBUG d1
+DEBUG d2
+DEBUG d3
+ERROR rate limit: messages suppressed suppressed=2
+ERROR fn
+`, r.String())
+}
+
+func TestRateLimit_byMessage(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := logxtest.NewRecorder()
+	l := logx.RateLimit(r, logx.RateLimitConfig{
+		Rate:      1,
+		Burst:     1,
+		ByMessage: true,
+		Now:       func() time.Time { return now },
+	})
+
+	l.Log(logx.LevelInfo, "a")
+	l.Log(logx.LevelInfo, "a")
+
+	now = now.Add(time.Second)
+	l.Log(logx.LevelInfo, "b")
+
+	now = now.Add(time.Second)
+	l.Log(logx.LevelInfo, "a")
+
+	// The limiting of 'a' ends when 'b' is logged.
+	assert.Equal(t, `INFO a
+INFO rate limit: messages suppressed message=a suppressed=1
+INFO b
+INFO a
+`, r.String())
+}
+
+func TestRateLimit_byKey(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := logxtest.NewRecorder()
+	l := logx.RateLimit(r, logx.RateLimitConfig{
+		Rate:  1,
+		Burst: 1,
+		ByKey: "user",
+		Now:   func() time.Time { return now },
+	})
+
+	l.Log(logx.LevelInfo, "m", "user", "u1")
+	l.Log(logx.LevelInfo, "m", "user", "u1")
+	l.Log(logx.LevelInfo, "m", "user", "u2")
+	l.Log(logx.LevelInfo, "m")
+	l.Log(logx.LevelInfo, "m")
+
+	now = now.Add(time.Second)
+	l.Log(logx.LevelInfo, "m", "user", "u1")
+
+	assert.Equal(t, `INFO m user=u1
+INFO m user=u2
+INFO m
+INFO rate limit: messages suppressed user=u1 suppressed=1
+INFO rate limit: messages suppressed suppressed=1
+INFO m user=u1
+`, r.String())
+}
+
+func TestRateLimit_noRate(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.RateLimit(r, logx.RateLimitConfig{})
+
+	for i := 0; i < 100; i++ {
+		l.Log(logx.LevelInfo, "m")
+	}
+	assert.Equal(t, 100, len(r.Messages))
+}
+
+func TestRateLimit_byKeyAbsent(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.RateLimit(r, logx.RateLimitConfig{Rate: 1, ByKey: "user"})
+
+	calls := 0
+	l.LogFn(logx.LevelInfo, func() (string, []interface{}) {
+		calls++
+		return "m", []interface{}{"k", 1}
+	})
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "INFO m k=1\n", r.String())
+}
+
+func TestRateLimit_summaryError(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := logxtest.NewRecorder()
+	l := logx.RateLimit(logx.NewHandlerLogger("", logx.HandlerFunc(func(e logx.Entry) error {
+		if e.Message == logx.RateLimitSummaryMessage {
+			return errors.New("broken")
+		}
+		return r.Handle(e)
+	})), logx.RateLimitConfig{Rate: 1, Now: func() time.Time { return now }})
+
+	l.Log(logx.LevelInfo, "a")
+	l.Log(logx.LevelInfo, "b")
+	now = now.Add(time.Second)
+
+	assert.EqualError(t, l.Log(logx.LevelInfo, "c"), "broken")
+	assert.Equal(t, "INFO a\nINFO c\n", r.String())
+}
+
+func TestRateLimit_Flush(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := logxtest.NewRecorder()
+	l := logx.RateLimit(r, logx.RateLimitConfig{
+		Rate:  1,
+		ByKey: "user",
+		Now:   func() time.Time { return now },
+	})
+
+	l.Log(logx.LevelInfo, "a", "user", "u1")
+	l.Log(logx.LevelInfo, "a", "user", "u1")
+	l.Log(logx.LevelInfo, "a", "user", "u1")
+	l.Log(logx.LevelWarn, "w")
+	l.Log(logx.LevelWarn, "w")
+
+	// The limiting has not ended yet.
+	now = now.Add(time.Second / 2)
+	a := assert.New(t)
+	a.NoError(logx.Flush(l))
+	a.Equal(`INFO a user=u1
+WARN w
+INFO rate limit: messages suppressed user=u1 suppressed=2
+WARN rate limit: messages suppressed suppressed=1
+`, r.String())
+
+	// Nothing is pending.
+	now = now.Add(time.Second)
+	a.NoError(logx.Flush(l))
+	l.Log(logx.LevelWarn, "w")
+	a.Equal(5, len(r.Messages))
+}
+
+func TestRateLimit_maxBuckets(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := logxtest.NewRecorder()
+	l := logx.RateLimit(r, logx.RateLimitConfig{
+		Rate:       1,
+		ByMessage:  true,
+		MaxBuckets: 2,
+		Now:        func() time.Time { return now },
+	})
+
+	l.Log(logx.LevelInfo, "a")
+	l.Log(logx.LevelInfo, "a")
+	l.Log(logx.LevelInfo, "b")
+	l.Log(logx.LevelInfo, "a") // 'a' becomes the most recently used.
+	l.Log(logx.LevelInfo, "c") // 'b' is evicted.
+	l.Log(logx.LevelInfo, "a") // 'a' is still limited, its burst is not renewed.
+	l.Log(logx.LevelInfo, "d") // 'c' is evicted.
+	l.Log(logx.LevelInfo, "e") // 'a' is evicted, with the pending summary.
+
+	assert.Equal(t, `INFO a
+INFO b
+INFO c
+INFO d
+INFO rate limit: messages suppressed message=a suppressed=3
+INFO e
+`, r.String())
+}
@@ -0,0 +1,68 @@
+package logx
+
+// RecoveredPanicMessage is the message logged by Recover() and its variants.
+const RecoveredPanicMessage = "panic recovered"
+
+// Recover recovers from a panic and logs it at LevelError to the given Logger, the panic is swallowed.
+// It must be called directly by defer, e.g.
+//
+//	defer logx.Recover(logger, "job", jobName)
+//
+// The message RecoveredPanicMessage is logged with the key-values, followed by the key-value pairs
+// panic=VALUE and stack=STACK. Frames of the Go runtime and this package are dropped from the stack;
+// if the panic value is an error carrying a stack, see ErrorStack(), that stack is used.
+func Recover(logger Logger, keyValues ...interface{}) {
+	if p := recover(); p != nil {
+		logPanic(logger, LevelError, p, keyValues)
+	}
+}
+
+// RecoverRepanic is similar to Recover(), but logs the panic at LevelFatal, flushes the Loggers in
+// the chain with Flush(), then panics again with the same value.
+// It must be called directly by defer.
+func RecoverRepanic(logger Logger, keyValues ...interface{}) {
+	if p := recover(); p != nil {
+		logPanic(logger, LevelFatal, p, keyValues)
+		Flush(logger)
+		panic(p)
+	}
+}
+
+// Go starts a goroutine running fn, a panic in fn is recovered and logged as Recover() does,
+// so that the program does not crash. Use GoRepanic() if the program should crash.
+func Go(logger Logger, fn func(), keyValues ...interface{}) {
+	go func() {
+		defer Recover(logger, keyValues...)
+		fn()
+	}()
+}
+
+// GoRepanic starts a goroutine running fn, a panic in fn is logged and the Loggers are flushed as
+// RecoverRepanic() does, then it panics again, which crashes the program.
+// It is useful when the panic leaves the program in an unknown state, but the log messages should be kept.
+func GoRepanic(logger Logger, fn func(), keyValues ...interface{}) {
+	go func() {
+		defer RecoverRepanic(logger, keyValues...)
+		fn()
+	}()
+}
+
+// logPanic logs the panic value with the stack.
+func logPanic(logger Logger, level Level, p interface{}, keyValues []interface{}) {
+	if logger == nil {
+		return
+	}
+
+	var pcs []uintptr
+	if err, ok := p.(error); ok {
+		pcs = ErrorStack(err)
+	}
+	if len(pcs) == 0 {
+		pcs = captureStack(3, defaultStackDepth)
+	}
+
+	kv := make([]interface{}, 0, len(keyValues)+4)
+	kv = append(kv, keyValues...)
+	kv = append(kv, "panic", p, DefaultStackKey, formatStack(pcs, defaultStackDepth))
+	logger.Log(level, RecoveredPanicMessage, kv...)
+}
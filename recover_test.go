@@ -0,0 +1,115 @@
+package logx_test
+
+import (
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func panicWith(v interface{}) {
+	panic(v)
+}
+
+func TestRecover(t *testing.T) {
+	r := logxtest.NewRecorder()
+	func() {
+		defer logx.Recover(r, "k", 1)
+		panicWith("oops")
+	}()
+	func() {
+		defer logx.Recover(r)
+	}()
+
+	a := assert.New(t)
+	a.Equal(1, len(r.Messages))
+
+	m := r.Messages[0]
+	a.Equal(logx.LevelError, m.Level)
+	a.Equal(logx.RecoveredPanicMessage, m.Message)
+	a.Equal([]interface{}{"k", 1, "panic", "oops", "stack"}, m.KeyValues[:5])
+
+	stack := m.KeyValues[5].(string)
+	a.True(strings.HasPrefix(stack, "github.com/cmstar/go-logx_test.panicWith\n\t"), stack)
+	a.NotContains(stack, "runtime.")
+}
+
+func TestRecover_errorStack(t *testing.T) {
+	r := logxtest.NewRecorder()
+	func() {
+		defer logx.Recover(r)
+		panic(createStackError())
+	}()
+
+	stack := r.Messages[0].KeyValues[3].(string)
+	assert.True(t, strings.HasPrefix(stack, "github.com/cmstar/go-logx_test.createStackError\n\t"), stack)
+}
+
+func TestRecoverRepanic(t *testing.T) {
+	r := logxtest.NewRecorder()
+	d := logx.Dedup(r, logx.DedupConfig{})
+	d.Log(logx.LevelInfo, "a")
+	d.Log(logx.LevelInfo, "a")
+
+	err := errors.New("e")
+	a := assert.New(t)
+	a.PanicsWithValue(err, func() {
+		defer logx.RecoverRepanic(d)
+		panic(err)
+	})
+
+	a.Equal(3, len(r.Messages))
+	a.Equal(logx.LevelFatal, r.Messages[1].Level)
+	a.Equal(err, r.Messages[1].KeyValues[1])
+	a.Equal("a", r.Messages[2].Message) // Flushed.
+}
+
+func TestGo(t *testing.T) {
+	entries := make(chan logx.Entry, 1)
+	l := logx.NewHandlerLogger("", logx.HandlerFunc(func(e logx.Entry) error {
+		entries <- e
+		return nil
+	}))
+
+	logx.Go(l, func() { panic("oops") }, "k", 1)
+
+	e := <-entries
+	a := assert.New(t)
+	a.Equal(logx.LevelError, e.Level)
+	a.Equal([]interface{}{"k", 1, "panic", "oops"}, e.KeyValues[:4])
+}
+
+func TestGoRepanic(t *testing.T) {
+	// The panic crashes the program, run it in a child process.
+	if os.Getenv("LOGX_TEST_GO_REPANIC") == "1" {
+		d := logx.Dedup(logx.NewStdLogger(log.New(os.Stdout, "", 0)), logx.DedupConfig{})
+		d.Log(logx.LevelInfo, "a")
+		d.Log(logx.LevelInfo, "a")
+
+		logx.GoRepanic(d, func() { panic("oops") }, "k", 1)
+		select {}
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestGoRepanic$")
+	cmd.Env = append(os.Environ(), "LOGX_TEST_GO_REPANIC=1")
+	out, err := cmd.Output()
+
+	a := assert.New(t)
+	var exitErr *exec.ExitError
+	if a.True(errors.As(err, &exitErr), "the program should crash: %v", err) {
+		a.Contains(string(exitErr.Stderr), "panic: oops")
+	}
+
+	lines := strings.Split(string(out), "\n")
+	if a.True(len(lines) >= 3, string(out)) {
+		a.Equal("INFO a", lines[0])
+		a.True(strings.HasPrefix(lines[1], "FATAL panic recovered k=1 panic=oops stack="), lines[1])
+		a.Contains(string(out), "INFO a repeated=1") // Flushed.
+	}
+}
@@ -0,0 +1,302 @@
+package logx
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactMask is the default value of RedactConfig.Mask.
+const DefaultRedactMask = "***"
+
+// Some regular expressions for RedactConfig.Patterns.
+var (
+	// CreditCardPattern matches credit card numbers, which are 13 to 19 digits, can be separated by spaces or dashes.
+	// The Logger created by Redact masks only the numbers passing the Luhn check, other digit runs,
+	// such as timestamps and IDs, are kept.
+	CreditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+	// EmailPattern matches email addresses.
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+	// BearerTokenPattern matches bearer tokens like 'Bearer xxxx', which are used in the HTTP Authorization header.
+	BearerTokenPattern = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)
+)
+
+// Redactable can be implemented by types which contain sensitive data.
+// The Logger created by Redact replaces values implementing Redactable with the result of Redact().
+type Redactable interface {
+	// Redact returns a representation of the value without sensitive data.
+	Redact() interface{}
+}
+
+// RedactConfig configures the Logger created by Redact.
+type RedactConfig struct {
+	// Keys are the names of keys whose values are replaced with Mask. Keys are case-insensitive.
+	//
+	// Keys are dotted-path aware. Values of type map[string]interface{} and structs are walked recursively,
+	// e.g. the path of 'p' in the key-value pair db={"p": "v"} is 'db.p'; the path of a struct field is its name.
+	// The elements of a []interface{} have the path of the slice.
+	// A path matches a key if it equals to the key, or ends with '.' and the key,
+	// e.g. 'password' matches 'password' and 'db.password'; 'db.password' matches 'app.db.password'.
+	Keys []string
+
+	// Patterns are regular expressions applied to messages, string values, and the text of errors and
+	// fmt.Stringer values, the matched parts are replaced with Mask.
+	// See CreditCardPattern, EmailPattern and BearerTokenPattern.
+	Patterns []*regexp.Regexp
+
+	// Mask replaces the sensitive data. If it is empty, DefaultRedactMask is used.
+	Mask string
+}
+
+// Redact wraps the given Logger, returns a new Logger which masks sensitive data in log messages,
+// before the messages are sent to the given Logger, thus before any formatter runs.
+//
+// Values of keys in config.Keys are replaced with config.Mask; values implementing Redactable are
+// replaced with the result of their Redact(), which is not redacted further; config.Patterns are applied to
+// the message and string values. An error or fmt.Stringer value is replaced with its redacted text,
+// and a struct with a map[string]interface{} of its exported fields, only if anything is masked;
+// otherwise the value is kept as is.
+// The given keyValues are not modified, a copy is used when needed.
+func Redact(raw Logger, config RedactConfig) Logger {
+	if config.Mask == "" {
+		config.Mask = DefaultRedactMask
+	}
+
+	keys := make([]string, len(config.Keys))
+	for i, k := range config.Keys {
+		keys[i] = strings.ToLower(k)
+	}
+	return redactor{raw, config, keys}
+}
+
+// redactor is a Logger which masks sensitive data.
+type redactor struct {
+	logger Logger
+	config RedactConfig
+	keys   []string // The lowercase config.Keys.
+}
+
+func (r redactor) Log(level Level, message string, keyValues ...interface{}) error {
+	message, keyValues = r.redact(message, keyValues)
+	return r.logger.Log(level, message, keyValues...)
+}
+
+func (r redactor) LogFn(level Level, messageFactory func() (message string, keyValues []interface{})) error {
+	return r.logger.LogFn(level, func() (string, []interface{}) {
+		return r.redact(messageFactory())
+	})
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (r redactor) Unwrap() Logger {
+	return r.logger
+}
+
+// Enabled implements LevelEnabler.Enabled(), the wrapped Logger decides.
+func (r redactor) Enabled(level Level) bool {
+	return Enabled(r.logger, level)
+}
+
+// redact returns the redacted message and key-values.
+func (r redactor) redact(message string, keyValues []interface{}) (string, []interface{}) {
+	message = r.redactString(message)
+	if len(keyValues) == 0 {
+		return message, keyValues
+	}
+
+	res := make([]interface{}, len(keyValues))
+	length := len(keyValues)
+	for i := 0; i < length-1; i += 2 {
+		k := keyValues[i]
+		res[i] = k
+		res[i+1], _ = r.redactValue(fmt.Sprint(k), keyValues[i+1], 0)
+	}
+
+	if length%2 != 0 {
+		res[length-1], _ = r.redactValue("UNKNOWN", keyValues[length-1], 0)
+	}
+	return message, res
+}
+
+// maxRedactDepth limits the depth of the values walked by redactValue, values may refer to themselves.
+const maxRedactDepth = 10
+
+// redactValue returns the redacted value of the given path, and whether the value is changed.
+// The value is returned as is if nothing is masked.
+func (r redactor) redactValue(path string, value interface{}, depth int) (interface{}, bool) {
+	if r.matchKey(path) {
+		return r.config.Mask, true
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+
+	case Redactable:
+		return v.Redact(), true
+
+	case string:
+		res := r.redactString(v)
+		return res, res != v
+
+	case map[string]interface{}:
+		if depth >= maxRedactDepth {
+			return value, false
+		}
+
+		m := make(map[string]interface{}, len(v))
+		changed := false
+		for k, item := range v {
+			var itemChanged bool
+			m[k], itemChanged = r.redactValue(path+"."+k, item, depth+1)
+			changed = changed || itemChanged
+		}
+		if !changed {
+			return value, false
+		}
+		return m, true
+
+	case []interface{}:
+		if depth >= maxRedactDepth {
+			return value, false
+		}
+
+		items := make([]interface{}, len(v))
+		changed := false
+		for i, item := range v {
+			var itemChanged bool
+			items[i], itemChanged = r.redactValue(path, item, depth+1)
+			changed = changed || itemChanged
+		}
+		if !changed {
+			return value, false
+		}
+		return items, true
+
+	case error, fmt.Stringer:
+		return r.redactText(value)
+	}
+
+	if depth >= maxRedactDepth {
+		return value, false
+	}
+	return r.redactStruct(path, value, depth)
+}
+
+// redactText returns the redacted text of an error or fmt.Stringer value, if anything is masked;
+// otherwise returns the value.
+func (r redactor) redactText(value interface{}) (interface{}, bool) {
+	if len(r.config.Patterns) == 0 {
+		return value, false
+	}
+
+	// The methods may not handle nil receivers.
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return value, false
+	}
+
+	var text string
+	if err, ok := value.(error); ok {
+		text = err.Error()
+	} else {
+		text = value.(fmt.Stringer).String()
+	}
+
+	res := r.redactString(text)
+	if res == text {
+		return value, false
+	}
+	return res, true
+}
+
+// redactStruct walks the exported fields of a struct or a pointer to a struct. If anything is masked,
+// returns a map[string]interface{} of the redacted fields; otherwise returns the value.
+func (r redactor) redactStruct(path string, value interface{}, depth int) (interface{}, bool) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return value, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return value, false
+	}
+
+	t := rv.Type()
+	m := make(map[string]interface{}, t.NumField())
+	changed := false
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { // Unexported.
+			continue
+		}
+
+		var fieldChanged bool
+		m[f.Name], fieldChanged = r.redactValue(path+"."+f.Name, rv.Field(i).Interface(), depth+1)
+		changed = changed || fieldChanged
+	}
+
+	if !changed {
+		return value, false
+	}
+	return m, true
+}
+
+// redactString applies the patterns to the given string.
+func (r redactor) redactString(s string) string {
+	for _, p := range r.config.Patterns {
+		if p == CreditCardPattern {
+			s = p.ReplaceAllStringFunc(s, func(m string) string {
+				if !luhnValid(m) {
+					return m
+				}
+				return r.config.Mask
+			})
+			continue
+		}
+		s = p.ReplaceAllLiteralString(s, r.config.Mask)
+	}
+	return s
+}
+
+// luhnValid reports whether the digits in the given string pass the Luhn check, other characters are ignored.
+func luhnValid(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// matchKey reports whether the given path matches any of the keys.
+func (r redactor) matchKey(path string) bool {
+	if len(r.keys) == 0 {
+		return false
+	}
+
+	path = strings.ToLower(path)
+	for _, k := range r.keys {
+		if path == k || strings.HasSuffix(path, "."+k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package logx_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+type secretUser struct {
+	Name     string
+	Password string
+}
+
+func (u secretUser) Redact() interface{} {
+	return secretUser{u.Name, "-"}
+}
+
+func TestRedact(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.Redact(r, logx.RedactConfig{
+		Keys:     []string{"password", "db.token"},
+		Patterns: []*regexp.Regexp{logx.CreditCardPattern, logx.EmailPattern, logx.BearerTokenPattern},
+	})
+
+	a := assert.New(t)
+
+	keyValues := []interface{}{"PassWord", "p1", "user", "u1"}
+	l.Log(logx.LevelInfo, "login", keyValues...)
+	a.Equal("INFO login PassWord=*** user=u1\n", r.String())
+	a.Equal("p1", keyValues[1], "the given keyValues should not be modified")
+
+	r.Messages = nil
+	l.Log(logx.LevelInfo, "mail a.b@example.com card 4111 1111 1111 1111",
+		"auth", "Bearer abc.def-123", "n", 1234, "x")
+	a.Equal("INFO mail *** card *** auth=*** n=1234 UNKNOWN=x\n", r.String())
+
+	r.Messages = nil
+	l.LogFn(logx.LevelInfo, func() (string, []interface{}) {
+		return "nested", []interface{}{
+			"db", map[string]interface{}{"token": "t", "password": "p", "host": "h"},
+			"app.db.token", "t",
+			"token", "t",
+			"u", secretUser{"n", "p"},
+		}
+	})
+	a.Equal(1, len(r.Messages))
+	a.Equal([]interface{}{
+		"db", map[string]interface{}{"token": "***", "password": "***", "host": "h"},
+		"app.db.token", "***",
+		"token", "t",
+		"u", secretUser{"n", "-"},
+	}, r.Messages[0].KeyValues)
+}
+
+type account struct {
+	Name   string
+	Secret string
+	Mail   string
+	Nested *account
+	hidden string
+}
+
+type mailer string
+
+func (m mailer) String() string { return "mail to " + string(m) }
+
+func TestRedact_values(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.Redact(r, logx.RedactConfig{
+		Keys:     []string{"secret"},
+		Patterns: []*regexp.Regexp{logx.CreditCardPattern, logx.EmailPattern},
+	})
+
+	plainErr := errors.New("plain")
+	plain := struct{ Name string }{"n"}
+	l.Log(logx.LevelInfo, "m",
+		"err", fmt.Errorf("failed for a@example.com"),
+		"plainErr", plainErr,
+		"to", mailer("a@example.com"),
+		"list", []interface{}{"a@example.com", map[string]interface{}{"secret": "s"}, 1},
+		"acc", &account{Name: "n", Secret: "s", Nested: &account{Mail: "a@example.com"}, hidden: "h"},
+		"plain", plain,
+	)
+
+	a := assert.New(t)
+	a.Equal([]interface{}{
+		"err", "failed for ***",
+		"plainErr", plainErr,
+		"to", "mail to ***",
+		"list", []interface{}{"***", map[string]interface{}{"secret": "***"}, 1},
+		"acc", map[string]interface{}{
+			"Name":   "n",
+			"Secret": "***",
+			"Mail":   "",
+			"Nested": map[string]interface{}{"Name": "", "Secret": "***", "Mail": "***", "Nested": (*account)(nil)},
+		},
+		"plain", plain,
+	}, r.Messages[0].KeyValues)
+}
+
+func TestRedact_creditCard(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.Redact(r, logx.RedactConfig{Patterns: []*regexp.Regexp{logx.CreditCardPattern}})
+
+	// The second number fails the Luhn check, e.g. a timestamp in milliseconds.
+	l.Log(logx.LevelInfo, "card 4111-1111-1111-1111 at 1609459200000123")
+	assert.Equal(t, "INFO card *** at 1609459200000123\n", r.String())
+}
+
+func TestRedact_mask(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.Redact(r, logx.RedactConfig{
+		Keys:     []string{"unknown"},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`\d+`)},
+		Mask:     "#",
+	})
+
+	l.Log(logx.LevelInfo, "a1b22", "k", "33", "v")
+	assert.Equal(t, "INFO a#b# k=# UNKNOWN=#\n", r.String())
+}
@@ -0,0 +1,195 @@
+package logx
+
+import (
+	"sync"
+	"time"
+)
+
+// RingBufferedAtKey is the key of the time when a dumped message was buffered.
+const RingBufferedAtKey = "buffered_at"
+
+// defaultRingSize is the default value of RingConfig.Size.
+const defaultRingSize = 100
+
+// RingConfig configures the RingLogger created by NewRingLogger.
+type RingConfig struct {
+	// Size is the number of log messages kept in memory. If it is not positive, 100 is used.
+	Size int
+
+	// TriggerLevels is the mask of levels which trigger the dump of the buffered messages.
+	// If it is 0, LevelAtLeast(LevelError) is used.
+	TriggerLevels Level
+
+	// BufferLevels is the mask of levels which are buffered. Messages at other levels, which are not trigger levels,
+	// are logged to the target immediately. If it is 0, LevelDebug|LevelTrace is used; use -1 to buffer all levels.
+	BufferLevels Level
+
+	// Now returns the current time, which is recorded when a message is buffered. If it is nil, time.Now is used.
+	// It is useful for testing.
+	Now func() time.Time
+}
+
+// RingLogger is a Logger which keeps the last N log messages in memory, and dumps them to a target Logger
+// when a message at a trigger level, such as LevelError, is logged. It gives debug-level context around
+// failures, without persisting all debug output.
+// Dumped messages have an extra key-value pair RingBufferedAtKey=TIME, the time when they were buffered.
+// It is safe for concurrent use if the target Logger is.
+//
+// It implements Flusher, Flush() dumps the buffered messages; and LevelEnabler, all levels are enabled.
+type RingLogger struct {
+	target Logger
+	config RingConfig
+
+	mu    sync.Mutex
+	ring  []ringEntry
+	start int // The index of the oldest message.
+	count int // The number of messages in the ring.
+}
+
+// ringEntry is a log message. If factory is not nil, message and keyValues are produced by it.
+type ringEntry struct {
+	level      Level
+	message    string
+	keyValues  []interface{}
+	factory    func() (string, []interface{})
+	bufferedAt time.Time // Zero if the message is not buffered.
+}
+
+var _ Logger = (*RingLogger)(nil)
+
+// NewRingLogger creates a new RingLogger which dumps the buffered messages to the target Logger.
+func NewRingLogger(target Logger, config RingConfig) *RingLogger {
+	if config.Size <= 0 {
+		config.Size = defaultRingSize
+	}
+	if config.TriggerLevels == 0 {
+		config.TriggerLevels = LevelAtLeast(LevelError)
+	}
+	if config.BufferLevels == 0 {
+		config.BufferLevels = LevelDebug | LevelTrace
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &RingLogger{
+		target: target,
+		config: config,
+		ring:   make([]ringEntry, config.Size),
+	}
+}
+
+// Log implements Logger.Log().
+// A message at a trigger level makes the buffered messages be logged to the target first, from the oldest,
+// then the message itself is logged, even if logging some buffered messages failed.
+// The key-values of a buffered message are copied, the caller can reuse the slice.
+func (l *RingLogger) Log(level Level, message string, keyValues ...interface{}) error {
+	return l.log(ringEntry{level: level, message: message, keyValues: keyValues})
+}
+
+// LogFn implements Logger.LogFn().
+// The messageFactory of a buffered message is invoked only when the message is dumped, thus buffering is cheap.
+// The factory of a buffered message must be pure: it may be invoked long after LogFn() returns, on the goroutine
+// logging the trigger message, or never; it must only read values which do not change after LogFn() is called,
+// and be safe to be called from other goroutines. Use Log() to capture the current values of mutable states.
+func (l *RingLogger) LogFn(level Level, messageFactory func() (string, []interface{})) error {
+	return l.log(ringEntry{level: level, factory: messageFactory})
+}
+
+// Enabled implements LevelEnabler.Enabled(), all levels are enabled, since all messages are buffered.
+func (l *RingLogger) Enabled(level Level) bool {
+	return true
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the target Logger.
+func (l *RingLogger) Unwrap() Logger {
+	return l.target
+}
+
+// Flush implements Flusher.Flush(), it logs the buffered messages to the target, and empties the buffer.
+func (l *RingLogger) Flush() error {
+	return l.dump(l.drain())
+}
+
+func (l *RingLogger) log(e ringEntry) error {
+	switch {
+	case (l.config.TriggerLevels & e.level) == e.level:
+		dumpErr := l.dump(l.drain())
+		return joinErrors(dumpErr, l.write(e))
+
+	case (l.config.BufferLevels & e.level) != e.level:
+		return l.write(e)
+	}
+
+	e.bufferedAt = l.config.Now()
+	if e.factory == nil {
+		e.keyValues = append([]interface{}(nil), e.keyValues...)
+	}
+
+	l.mu.Lock()
+	size := len(l.ring)
+	if l.count < size {
+		l.ring[(l.start+l.count)%size] = e
+		l.count++
+	} else {
+		l.ring[l.start] = e
+		l.start = (l.start + 1) % size
+	}
+	l.mu.Unlock()
+	return nil
+}
+
+// drain removes all buffered messages, returns them from the oldest.
+func (l *RingLogger) drain() []ringEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count == 0 {
+		return nil
+	}
+
+	size := len(l.ring)
+	entries := make([]ringEntry, l.count)
+	for i := range entries {
+		idx := (l.start + i) % size
+		entries[i] = l.ring[idx]
+		l.ring[idx] = ringEntry{} // Release the references.
+	}
+	l.start = 0
+	l.count = 0
+	return entries
+}
+
+// dump logs the entries to the target, all entries are logged even if some of them fail,
+// the errors are combined.
+func (l *RingLogger) dump(entries []ringEntry) error {
+	var errs []error
+	for _, e := range entries {
+		errs = append(errs, l.write(e))
+	}
+	return joinErrors(errs...)
+}
+
+// write logs the entry to the target, with the time when it was buffered, if any.
+func (l *RingLogger) write(e ringEntry) error {
+	if e.bufferedAt.IsZero() {
+		if e.factory != nil {
+			return l.target.LogFn(e.level, e.factory)
+		}
+		return l.target.Log(e.level, e.message, e.keyValues...)
+	}
+
+	if e.factory != nil {
+		return l.target.LogFn(e.level, func() (string, []interface{}) {
+			message, keyValues := e.factory()
+			return message, withBufferedAt(keyValues, e.bufferedAt)
+		})
+	}
+	return l.target.Log(e.level, e.message, withBufferedAt(e.keyValues, e.bufferedAt)...)
+}
+
+// withBufferedAt returns a copy of the key-values with RingBufferedAtKey appended.
+func withBufferedAt(keyValues []interface{}, t time.Time) []interface{} {
+	res := make([]interface{}, 0, len(keyValues)+2)
+	res = append(res, keyValues...)
+	return append(res, RingBufferedAtKey, t)
+}
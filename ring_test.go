@@ -0,0 +1,137 @@
+package logx_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingLogger(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.NewRingLogger(r, logx.RingConfig{
+		Size:         3,
+		BufferLevels: logx.LevelTrace | logx.LevelDebug | logx.LevelInfo,
+		Now:          fakeClock(time.Second),
+	})
+
+	invoked := 0
+	l.LogFn(logx.LevelDebug, func() (string, []interface{}) {
+		t.Fatal("the factory should not be invoked, the message is overwritten")
+		return "", nil
+	})
+	l.LogFn(logx.LevelTrace, func() (string, []interface{}) {
+		invoked++
+		return "t2", nil
+	})
+	l.Log(logx.LevelInfo, "i3", "k", 3)
+	l.Log(logx.LevelDebug, "d4")
+
+	a := assert.New(t)
+	a.Equal(0, invoked)
+	a.Empty(r.Messages)
+
+	l.Log(logx.LevelError, "e5")
+	l.Log(logx.LevelDebug, "d6")
+	l.Log(logx.LevelFatal, "f7")
+
+	a.Equal(1, invoked)
+	a.Equal(`TRACE t2 buffered_at=2021-01-01 00:00:02 +0000 UTC
+INFO i3 k=3 buffered_at=2021-01-01 00:00:03 +0000 UTC
+DEBUG d4 buffered_at=2021-01-01 00:00:04 +0000 UTC
+ERROR e5
+DEBUG d6 buffered_at=2021-01-01 00:00:05 +0000 UTC
+FATAL f7
+`, r.String())
+
+	a.True(logx.Enabled(l, logx.LevelTrace))
+	a.Same(r, l.Unwrap())
+}
+
+func TestRingLogger_Flush(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.NewRingLogger(r, logx.RingConfig{Now: fakeClock(time.Second)})
+
+	// INFO is passed by default.
+	l.Log(logx.LevelDebug, "d1")
+	l.Log(logx.LevelInfo, "i2")
+	l.LogFn(logx.LevelDebug, func() (string, []interface{}) { return "d3", []interface{}{"k", 3} })
+
+	a := assert.New(t)
+	a.Equal("INFO i2\n", r.String())
+
+	a.NoError(logx.Flush(logx.Op(l)))
+	a.Equal(`INFO i2
+DEBUG d1 buffered_at=2021-01-01 00:00:01 +0000 UTC
+DEBUG d3 k=3 buffered_at=2021-01-01 00:00:02 +0000 UTC
+`, r.String())
+
+	a.NoError(l.Flush())
+	a.Equal(3, len(r.Messages))
+}
+
+func TestRingLogger_copyKeyValues(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.NewRingLogger(r, logx.RingConfig{BufferLevels: -1, Now: fakeClock(time.Second)})
+
+	kv := []interface{}{"k", 1}
+	l.Log(logx.LevelWarn, "w", kv...)
+	kv[1] = 2
+	l.Log(logx.LevelError, "e")
+
+	assert.Equal(t, `WARN w k=1 buffered_at=2021-01-01 00:00:01 +0000 UTC
+ERROR e
+`, r.String())
+}
+
+func TestRingLogger_concurrent(t *testing.T) {
+	r := logxtest.NewRecorder()
+	mu := new(sync.Mutex)
+	l := logx.NewRingLogger(lockedLogger{mu, r}, logx.RingConfig{Size: 10})
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Log(logx.LevelDebug, "d")
+			}
+			l.Log(logx.LevelError, "e")
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	n := 0
+	for _, m := range r.Messages {
+		if m.Level == logx.LevelError {
+			n++
+		}
+	}
+	assert.Equal(t, 10, n)
+	assert.True(t, len(r.Messages) <= 10*11)
+}
+
+func TestRingLogger_dumpError(t *testing.T) {
+	r := logxtest.NewRecorder()
+	target := logx.NewHandlerLogger("", logx.HandlerFunc(func(e logx.Entry) error {
+		if e.Level == logx.LevelDebug {
+			return errors.New("broken")
+		}
+		return r.Handle(e)
+	}))
+	l := logx.NewRingLogger(target, logx.RingConfig{})
+
+	l.Log(logx.LevelDebug, "d")
+	l.Log(logx.LevelInfo, "i")
+
+	assert.EqualError(t, l.Log(logx.LevelError, "e"), "broken")
+	assert.Equal(t, 2, len(r.Messages))
+	assert.Equal(t, "e", r.Messages[1].Message)
+}
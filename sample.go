@@ -0,0 +1,264 @@
+package logx
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// SamplingDecision is the decision made by a sampling Logger on a log message.
+type SamplingDecision int
+
+const (
+	SampleLogged  SamplingDecision = iota + 1 // SampleLogged means the message is logged.
+	SampleDropped                             // SampleDropped means the message is dropped.
+)
+
+// SampleHook receives the decisions made by a sampling Logger.
+// The message is empty if the sampler makes the decision without invoking the LogFn factory.
+// It is called synchronously, thus it should be fast and safe for concurrent use.
+type SampleHook func(level Level, message string, decision SamplingDecision)
+
+// defaultSampleMaxKeys is the default value of FirstNSampleConfig.MaxKeys.
+const defaultSampleMaxKeys = 4096
+
+// RatioSampleConfig configures the Logger created by SampleRatio.
+type RatioSampleConfig struct {
+	// Ratio is the probability that a message is logged, in range [0, 1].
+	Ratio float64
+
+	// Levels is the mask of levels to be sampled. Messages at other levels are always logged.
+	// If it is 0, all levels are sampled.
+	Levels Level
+
+	// Rand returns a pseudo-random number in [0.0, 1.0). If it is nil, rand.Float64 is used.
+	// It is useful for testing.
+	Rand func() float64
+
+	// Hook, if not nil, receives the sampling decisions.
+	Hook SampleHook
+}
+
+// SampleRatio wraps the given Logger, returns a new Logger which logs messages randomly
+// with the probability config.Ratio.
+// The LogFn factory is never invoked for dropped messages.
+func SampleRatio(raw Logger, config RatioSampleConfig) Logger {
+	if config.Levels == 0 {
+		config.Levels = -1
+	}
+	if config.Rand == nil {
+		config.Rand = rand.Float64
+	}
+	return &ratioSampler{raw, config}
+}
+
+// ratioSampler is a Logger which samples log messages randomly.
+type ratioSampler struct {
+	logger Logger
+	config RatioSampleConfig
+}
+
+func (s *ratioSampler) Log(level Level, message string, keyValues ...interface{}) error {
+	if !s.sample(level, message) {
+		return nil
+	}
+	return s.logger.Log(level, message, keyValues...)
+}
+
+func (s *ratioSampler) LogFn(level Level, messageFactory func() (message string, keyValues []interface{})) error {
+	if !s.sample(level, "") {
+		return nil
+	}
+	return s.logger.LogFn(level, messageFactory)
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (s *ratioSampler) Unwrap() Logger {
+	return s.logger
+}
+
+// Enabled implements LevelEnabler.Enabled(), the wrapped Logger decides.
+func (s *ratioSampler) Enabled(level Level) bool {
+	return Enabled(s.logger, level)
+}
+
+// sample reports whether the message should be logged.
+func (s *ratioSampler) sample(level Level, message string) bool {
+	if (s.config.Levels & level) != level {
+		return true
+	}
+
+	logged := s.config.Rand() < s.config.Ratio
+	if s.config.Hook != nil {
+		decision := SampleDropped
+		if logged {
+			decision = SampleLogged
+		}
+		s.config.Hook(level, message, decision)
+	}
+	return logged
+}
+
+// FirstNSampleConfig configures the Logger created by SampleFirstN.
+type FirstNSampleConfig struct {
+	// Interval is the period in which messages are counted. If it is not positive, one second is used.
+	Interval time.Duration
+
+	// First is the number of messages logged in each interval before sampling starts.
+	First int
+
+	// Thereafter makes every Thereafter-th message be logged after the first messages in the interval.
+	// If it is not positive, all messages after the first messages are dropped.
+	Thereafter int
+
+	// Levels is the mask of levels to be sampled. Messages at other levels are always logged.
+	// If it is 0, all levels are sampled.
+	Levels Level
+
+	// MaxKeys limits the number of distinct messages being counted. If it is not positive, 4096 is used.
+	MaxKeys int
+
+	// Now returns the current time. If it is nil, time.Now is used.
+	// It is useful for testing.
+	Now func() time.Time
+
+	// Hook, if not nil, receives the sampling decisions.
+	Hook SampleHook
+}
+
+// SampleFirstN wraps the given Logger, returns a new Logger which counts messages by their level and message
+// in each interval; the first config.First messages in the interval are logged, after that, every
+// config.Thereafter-th message is logged.
+//
+// The LogFn factory is always invoked for sampled levels enabled on the given Logger, since the message
+// is needed for counting.
+func SampleFirstN(raw Logger, config FirstNSampleConfig) Logger {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.Levels == 0 {
+		config.Levels = -1
+	}
+	if config.MaxKeys <= 0 {
+		config.MaxKeys = defaultSampleMaxKeys
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &firstNSampler{
+		logger:   raw,
+		config:   config,
+		counters: make(map[sampleKey]*sampleCounter),
+	}
+}
+
+// firstNSampler is a Logger which logs the first N messages in an interval, then every Mth message.
+type firstNSampler struct {
+	logger Logger
+	config FirstNSampleConfig
+
+	mu       sync.Mutex
+	counters map[sampleKey]*sampleCounter
+}
+
+// sampleKey identifies a sampleCounter.
+type sampleKey struct {
+	level   Level
+	message string
+}
+
+// sampleCounter counts messages in an interval.
+type sampleCounter struct {
+	resetAt time.Time // The end of the current interval.
+	n       int       // The number of messages in the current interval.
+}
+
+func (s *firstNSampler) Log(level Level, message string, keyValues ...interface{}) error {
+	if !s.sample(level, message) {
+		return nil
+	}
+	return s.logger.Log(level, message, keyValues...)
+}
+
+func (s *firstNSampler) LogFn(level Level, messageFactory func() (message string, keyValues []interface{})) error {
+	if (s.config.Levels & level) != level {
+		return s.logger.LogFn(level, messageFactory)
+	}
+
+	if !Enabled(s.logger, level) {
+		return nil
+	}
+
+	message, keyValues := messageFactory()
+	return s.Log(level, message, keyValues...)
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (s *firstNSampler) Unwrap() Logger {
+	return s.logger
+}
+
+// Enabled implements LevelEnabler.Enabled(), the wrapped Logger decides.
+func (s *firstNSampler) Enabled(level Level) bool {
+	return Enabled(s.logger, level)
+}
+
+// sample reports whether the message should be logged.
+func (s *firstNSampler) sample(level Level, message string) bool {
+	if (s.config.Levels & level) != level {
+		return true
+	}
+
+	n := s.count(sampleKey{level, message})
+	logged := n <= s.config.First ||
+		(s.config.Thereafter > 0 && (n-s.config.First)%s.config.Thereafter == 0)
+
+	if s.config.Hook != nil {
+		decision := SampleDropped
+		if logged {
+			decision = SampleLogged
+		}
+		s.config.Hook(level, message, decision)
+	}
+	return logged
+}
+
+// count increases the counter of the given key, returns the number of messages in the current interval,
+// including the current one.
+func (s *firstNSampler) count(key sampleKey) int {
+	now := s.config.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[key]
+	if !ok {
+		if len(s.counters) >= s.config.MaxKeys {
+			s.prune(now)
+		}
+		c = new(sampleCounter)
+		s.counters[key] = c
+	}
+
+	if !now.Before(c.resetAt) {
+		c.resetAt = now.Add(s.config.Interval)
+		c.n = 0
+	}
+
+	c.n++
+	return c.n
+}
+
+// prune removes the counters whose interval has ended. If there are still too many counters,
+// all of them are removed. The lock must be held.
+func (s *firstNSampler) prune(now time.Time) {
+	for k, c := range s.counters {
+		if !now.Before(c.resetAt) {
+			delete(s.counters, k)
+		}
+	}
+
+	if len(s.counters) >= s.config.MaxKeys {
+		s.counters = make(map[sampleKey]*sampleCounter)
+	}
+}
@@ -0,0 +1,107 @@
+package logx_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleRatio(t *testing.T) {
+	randValues := []float64{0.1, 0.6, 0.4, 0.9}
+	randIndex := 0
+	var decisions []string
+
+	r := logxtest.NewRecorder()
+	l := logx.SampleRatio(r, logx.RatioSampleConfig{
+		Ratio:  0.5,
+		Levels: logx.LevelDebug | logx.LevelInfo,
+		Rand: func() float64 {
+			v := randValues[randIndex]
+			randIndex++
+			return v
+		},
+		Hook: func(level logx.Level, message string, decision logx.SamplingDecision) {
+			decisions = append(decisions, fmt.Sprintf("%v:%v:%v", level, message, decision))
+		},
+	})
+
+	l.Log(logx.LevelDebug, "d1")
+	l.Log(logx.LevelDebug, "d2")
+	l.Log(logx.LevelError, "e1")
+	l.LogFn(logx.LevelInfo, func() (string, []interface{}) { return "i1", nil })
+	l.LogFn(logx.LevelInfo, func() (string, []interface{}) {
+		t.Fatal("the factory should not be invoked")
+		return "", nil
+	})
+
+	a := assert.New(t)
+	a.Equal(`DEBUG d1
+ERROR e1
+INFO i1
+`, r.String())
+	a.Equal([]string{
+		"DEBUG:d1:1",
+		"DEBUG:d2:2",
+		"INFO::1",
+		"INFO::2",
+	}, decisions)
+}
+
+func TestSampleFirstN(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	dropped := 0
+
+	r := logxtest.NewRecorder()
+	l := logx.SampleFirstN(r, logx.FirstNSampleConfig{
+		Interval:   time.Second,
+		First:      2,
+		Thereafter: 3,
+		Levels:     logx.LevelInfo,
+		Now:        func() time.Time { return now },
+		Hook: func(level logx.Level, message string, decision logx.SamplingDecision) {
+			if decision == logx.SampleDropped {
+				dropped++
+			}
+		},
+	})
+
+	for i := 1; i <= 8; i++ {
+		n := i
+		l.LogFn(logx.LevelInfo, func() (string, []interface{}) { return "a", []interface{}{"n", n} })
+	}
+	l.Log(logx.LevelInfo, "b")
+	l.Log(logx.LevelWarn, "a")
+	l.Log(logx.LevelWarn, "a")
+	l.Log(logx.LevelWarn, "a")
+
+	// A new interval.
+	now = now.Add(time.Second)
+	l.Log(logx.LevelInfo, "a", "n", 9)
+
+	a := assert.New(t)
+	a.Equal(`INFO a n=1
+INFO a n=2
+INFO a n=5
+INFO a n=8
+INFO b
+WARN a
+WARN a
+WARN a
+INFO a n=9
+`, r.String())
+	a.Equal(4, dropped)
+}
+
+func TestSampleFirstN_noThereafter(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.SampleFirstN(r, logx.FirstNSampleConfig{First: 1})
+
+	for i := 0; i < 10; i++ {
+		l.Log(logx.LevelInfo, "a")
+	}
+	assert.Equal(t, 1, len(r.Messages))
+}
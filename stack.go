@@ -0,0 +1,232 @@
+package logx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// DefaultStackKey is the default value of StackConfig.Key.
+const DefaultStackKey = "stack"
+
+// defaultStackDepth is the default value of StackConfig.MaxDepth.
+const defaultStackDepth = 32
+
+// StackTracer is implemented by errors carrying the stack where they were created,
+// such as the errors returned by WithStack().
+type StackTracer interface {
+	// Callers returns the program counters of the stack, as returned by runtime.Callers().
+	Callers() []uintptr
+}
+
+// WithStack returns an error which wraps the given error, and records the stack of the calling goroutine.
+// The returned error implements StackTracer, and can be unwrapped by errors.Unwrap().
+// Returns nil if the given error is nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{err, captureStack(3, defaultStackDepth)}
+}
+
+// stackError is the error returned by WithStack().
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+func (e *stackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+func (e *stackError) Callers() []uintptr {
+	return e.pcs
+}
+
+// ErrorStack returns the program counters of the stack carried by the given error or the errors wrapped by it,
+// the innermost one is used, since it is the closest to where the error occurred.
+// Returns nil if there is no stack.
+//
+// Errors implementing StackTracer are supported, as well as errors having a method StackTrace() returning
+// a slice of uintptr-based frames, such as the errors created by github.com/pkg/errors.
+func ErrorStack(err error) []uintptr {
+	var res []uintptr
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if pcs := errorStack(e); len(pcs) > 0 {
+			res = pcs
+		}
+	}
+	return res
+}
+
+// errorStack returns the stack carried by the error, without unwrapping it.
+func errorStack(err error) []uintptr {
+	if st, ok := err.(StackTracer); ok {
+		return st.Callers()
+	}
+
+	// The pkg/errors style: StackTrace() errors.StackTrace, where StackTrace is []Frame and Frame is uintptr.
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() {
+		return nil
+	}
+
+	t := m.Type()
+	if t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Slice || t.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	v := m.Call(nil)[0]
+	pcs := make([]uintptr, v.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(v.Index(i).Uint())
+	}
+	return pcs
+}
+
+// StackConfig configures the Logger created by Stack.
+type StackConfig struct {
+	// Levels is the mask of levels whose messages get stack traces.
+	// If it is 0, LevelAtLeast(LevelError) is used.
+	Levels Level
+
+	// Key is the key of the stack trace. If it is empty, DefaultStackKey is used.
+	Key string
+
+	// MaxDepth limits the number of frames captured. If it is not positive, 32 is used.
+	MaxDepth int
+
+	// ErrorsOnly, if true, makes only the stacks carried by errors be logged,
+	// the stack of the calling goroutine is not captured.
+	ErrorsOnly bool
+}
+
+// Stack wraps the given Logger, returns a new Logger which attaches a stack trace to the log messages
+// at the levels in config.Levels, as a key-value pair.
+//
+// If a value in the key-values is an error carrying a stack, see ErrorStack(), the stack of the first such error
+// is used; otherwise the stack of the calling goroutine is captured.
+// Frames of the Go runtime and this package are dropped.
+//
+// The stack trace is formatted as lines of the function names, each followed by a line of the file and line number
+// indented by a tab, like the output of runtime/debug.Stack().
+func Stack(raw Logger, config StackConfig) Logger {
+	if config.Levels == 0 {
+		config.Levels = LevelAtLeast(LevelError)
+	}
+	if config.Key == "" {
+		config.Key = DefaultStackKey
+	}
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = defaultStackDepth
+	}
+	return stackLogger{raw, config}
+}
+
+// stackLogger is a Logger which attaches stack traces to log messages.
+type stackLogger struct {
+	logger Logger
+	config StackConfig
+}
+
+func (l stackLogger) Log(level Level, message string, keyValues ...interface{}) error {
+	if (l.config.Levels & level) != level {
+		return l.logger.Log(level, message, keyValues...)
+	}
+
+	pcs := l.callers()
+	return l.logger.Log(level, message, l.withStack(keyValues, pcs)...)
+}
+
+func (l stackLogger) LogFn(level Level, messageFactory func() (message string, keyValues []interface{})) error {
+	if (l.config.Levels&level) != level || !Enabled(l.logger, level) {
+		return l.logger.LogFn(level, messageFactory)
+	}
+
+	// The stack must be captured here, the factory may be invoked by other goroutines.
+	pcs := l.callers()
+	return l.logger.LogFn(level, func() (string, []interface{}) {
+		message, keyValues := messageFactory()
+		return message, l.withStack(keyValues, pcs)
+	})
+}
+
+// Unwrap implements Unwrapper.Unwrap(), returns the wrapped Logger.
+func (l stackLogger) Unwrap() Logger {
+	return l.logger
+}
+
+// Enabled implements LevelEnabler.Enabled(), the wrapped Logger decides.
+func (l stackLogger) Enabled(level Level) bool {
+	return Enabled(l.logger, level)
+}
+
+// callers captures the stack of the calling goroutine, returns nil if config.ErrorsOnly is true.
+func (l stackLogger) callers() []uintptr {
+	if l.config.ErrorsOnly {
+		return nil
+	}
+	return captureStack(3, l.config.MaxDepth)
+}
+
+// withStack returns a copy of the key-values with the stack appended.
+// The stack carried by errors in the key-values takes precedence over pcs.
+func (l stackLogger) withStack(keyValues []interface{}, pcs []uintptr) []interface{} {
+	for i := 1; i < len(keyValues); i += 2 {
+		if err, ok := keyValues[i].(error); ok {
+			if errPcs := ErrorStack(err); len(errPcs) > 0 {
+				pcs = errPcs
+				break
+			}
+		}
+	}
+
+	stack := formatStack(pcs, l.config.MaxDepth)
+	if stack == "" {
+		return keyValues
+	}
+
+	res := make([]interface{}, 0, len(keyValues)+2)
+	res = append(res, keyValues...)
+	return append(res, l.config.Key, stack)
+}
+
+// captureStack returns the program counters of the stack of the calling goroutine,
+// skip is the number of frames to skip, as runtime.Callers().
+func captureStack(skip, depth int) []uintptr {
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip, pcs)
+	return pcs[:n]
+}
+
+// formatStack formats the stack, frames of the Go runtime and this package are dropped,
+// at most depth frames are formatted.
+func formatStack(pcs []uintptr, depth int) string {
+	if len(pcs) == 0 {
+		return ""
+	}
+
+	b := new(strings.Builder)
+	frames := runtime.CallersFrames(pcs)
+	for n := 0; n < depth; {
+		f, more := frames.Next()
+		if f.PC != 0 && !isInternalFrame(f) {
+			if n > 0 {
+				b.WriteByte('\n')
+			}
+			fmt.Fprintf(b, "%s\n\t%s:%d", f.Function, f.File, f.Line)
+			n++
+		}
+
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package logx_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// Simulates the errors created by github.com/pkg/errors.
+type pkgFrame uintptr
+type pkgStackTrace []pkgFrame
+type pkgError struct {
+	msg   string
+	stack []uintptr
+}
+
+func (e pkgError) Error() string             { return e.msg }
+func (e pkgError) StackTrace() pkgStackTrace { return nil }
+
+func newPkgError(msg string) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &pkgErrorWithStack{pkgError{msg, pcs[:n]}}
+}
+
+type pkgErrorWithStack struct {
+	pkgError
+}
+
+func (e *pkgErrorWithStack) StackTrace() pkgStackTrace {
+	res := make(pkgStackTrace, len(e.stack))
+	for i, pc := range e.stack {
+		res[i] = pkgFrame(pc)
+	}
+	return res
+}
+
+func stackOf(m logxtest.LogMessage) string {
+	for i := 0; i < len(m.KeyValues)-1; i += 2 {
+		if m.KeyValues[i] == logx.DefaultStackKey {
+			return m.KeyValues[i+1].(string)
+		}
+	}
+	return ""
+}
+
+func createStackError() error {
+	return logx.WithStack(errors.New("e"))
+}
+
+func TestStack(t *testing.T) {
+	r := logxtest.NewRecorder()
+	op := logx.Op(logx.Stack(r, logx.StackConfig{}))
+
+	op.Warn("w")
+	op.Error("e")
+	op.LogFn(logx.LevelFatal, func() (string, []interface{}) { return "f", []interface{}{"k", 1} })
+
+	a := assert.New(t)
+	a.Equal(3, len(r.Messages))
+	a.Empty(r.Messages[0].KeyValues)
+
+	stack := stackOf(r.Messages[1])
+	a.True(strings.HasPrefix(stack, "github.com/cmstar/go-logx_test.TestStack\n\t"), stack)
+	a.NotContains(stack, "runtime.")
+	a.NotContains(stack, "github.com/cmstar/go-logx.")
+
+	a.Equal("k", r.Messages[2].KeyValues[0])
+	a.True(strings.HasPrefix(stackOf(r.Messages[2]), "github.com/cmstar/go-logx_test.TestStack\n\t"))
+}
+
+func TestStack_errors(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.Stack(r, logx.StackConfig{Levels: logx.LevelWarn, Key: "st", ErrorsOnly: true})
+
+	l.Log(logx.LevelWarn, "logx", "err", fmt.Errorf("wrapped: %w", createStackError()))
+	l.Log(logx.LevelWarn, "pkg", "err", newPkgError("p"))
+	l.Log(logx.LevelWarn, "noStack", "err", errors.New("x"))
+	l.Log(logx.LevelError, "level", "err", createStackError())
+
+	a := assert.New(t)
+	a.Equal(4, len(r.Messages))
+	a.Equal("st", r.Messages[0].KeyValues[2])
+	a.True(strings.HasPrefix(r.Messages[0].KeyValues[3].(string), "github.com/cmstar/go-logx_test.createStackError\n\t"))
+	a.True(strings.HasPrefix(r.Messages[1].KeyValues[3].(string), "github.com/cmstar/go-logx_test.TestStack_errors\n\t"))
+	a.Equal(2, len(r.Messages[2].KeyValues))
+	a.Equal(2, len(r.Messages[3].KeyValues))
+}
+
+func TestErrorStack(t *testing.T) {
+	a := assert.New(t)
+	a.Nil(logx.WithStack(nil))
+	a.Nil(logx.ErrorStack(errors.New("e")))
+	a.Nil(logx.ErrorStack(pkgError{msg: "e"}))
+
+	inner := createStackError()
+	outer := logx.WithStack(fmt.Errorf("outer: %w", inner))
+	a.Equal("outer: e", outer.Error())
+	a.Equal(inner.(logx.StackTracer).Callers(), logx.ErrorStack(outer))
+}
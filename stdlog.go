@@ -0,0 +1,160 @@
+package logx
+
+import (
+	"log"
+	"strings"
+)
+
+// StdLogWriter is an io.Writer which receives the output of a *log.Logger of the standard library,
+// parses each line and re-emits it to a Logger.
+// It is safe for concurrent use if the Logger is.
+//
+// Each call of Write() is treated as one log message. The header produced by the *log.Logger,
+// described by Prefix and Flags, is stripped, as well as the trailing newline.
+type StdLogWriter struct {
+	// Logger receives the log messages.
+	Logger Logger
+
+	// Level is the level of the log messages. If it is 0, the level is detected from the first word
+	// of the message, e.g. 'error: xx' and '[WARN] xx' are at LevelError and LevelWarn. The word is
+	// removed from the message only if it is delimited by a trailing ':' or by brackets, so that
+	// 'error connecting to db' is kept as is. If no level can be detected, LevelInfo is used.
+	Level Level
+
+	// Prefix is the prefix of the *log.Logger.
+	Prefix string
+
+	// Flags are the flags of the *log.Logger, such as log.LstdFlags.
+	Flags int
+}
+
+// NewStdLogWriter creates a new StdLogWriter which emits log messages to the given Logger at the given level,
+// the level can be 0 to detect levels from messages.
+// The prefix and flags of the *log.Logger writing to it are assumed to be empty.
+func NewStdLogWriter(logger Logger, level Level) *StdLogWriter {
+	return &StdLogWriter{
+		Logger: logger,
+		Level:  level,
+	}
+}
+
+// NewStdLogLogger creates a *log.Logger of the standard library, which writes to the given Logger through
+// a StdLogWriter. The level can be 0 to detect levels from messages.
+// It is useful for libraries accepting a *log.Logger, such as http.Server.ErrorLog.
+func NewStdLogLogger(logger Logger, level Level) *log.Logger {
+	return log.New(NewStdLogWriter(logger, level), "", 0)
+}
+
+// RedirectStdLog redirects the output of the global logger of the standard log package to the given Logger,
+// levels are detected from messages. The returned function restores the output, prefix and flags of
+// the global logger.
+//
+// The prefix and flags of the global logger are cleared during the redirection, since the Logger is
+// responsible for formatting.
+func RedirectStdLog(logger Logger) (restore func()) {
+	out, prefix, flags := log.Writer(), log.Prefix(), log.Flags()
+
+	log.SetOutput(NewStdLogWriter(logger, 0))
+	log.SetPrefix("")
+	log.SetFlags(0)
+
+	return func() {
+		log.SetOutput(out)
+		log.SetPrefix(prefix)
+		log.SetFlags(flags)
+	}
+}
+
+// Write implements io.Writer.Write(). It always returns len(p), and the error returned by the Logger.
+func (w *StdLogWriter) Write(p []byte) (n int, err error) {
+	message := strings.TrimSuffix(string(p), "\n")
+	message = w.stripHeader(message)
+
+	level := w.Level
+	if level == 0 {
+		level, message = detectLevel(message)
+	}
+
+	return len(p), w.Logger.Log(level, message)
+}
+
+// stripHeader removes the prefix, date, time and file name written by the *log.Logger.
+func (w *StdLogWriter) stripHeader(line string) string {
+	if w.Flags&log.Lmsgprefix == 0 {
+		line = strings.TrimPrefix(line, w.Prefix)
+	}
+
+	// Date: 2009/01/23
+	if w.Flags&log.Ldate != 0 {
+		line = skipField(line, len("2009/01/23 "))
+	}
+
+	// Time: 01:23:23 or 01:23:23.123123
+	if w.Flags&log.Lmicroseconds != 0 {
+		line = skipField(line, len("01:23:23.123123 "))
+	} else if w.Flags&log.Ltime != 0 {
+		line = skipField(line, len("01:23:23 "))
+	}
+
+	// File: /a/b/c/d.go:23 or d.go:23
+	if w.Flags&(log.Lshortfile|log.Llongfile) != 0 {
+		if i := strings.Index(line, ": "); i >= 0 {
+			line = line[i+2:]
+		}
+	}
+
+	if w.Flags&log.Lmsgprefix != 0 {
+		line = strings.TrimPrefix(line, w.Prefix)
+	}
+	return line
+}
+
+// skipField removes the first n bytes from s, if s is shorter than n, returns an empty string.
+func skipField(s string, n int) string {
+	if len(s) < n {
+		return ""
+	}
+	return s[n:]
+}
+
+// detectLevel detects the level of the message by its first word, returns the level and the message.
+// The word is removed from the message if it is delimited, like 'error:' or '[ERROR]'.
+// If no level can be detected, returns LevelInfo and the message.
+func detectLevel(message string) (Level, string) {
+	word := message
+	rest := ""
+	if i := strings.IndexByte(message, ' '); i >= 0 {
+		word = message[:i]
+		rest = strings.TrimLeft(message[i+1:], " ")
+	}
+
+	name := strings.TrimSuffix(word, ":")
+	delimited := len(name) < len(word)
+	if len(name) > 2 && name[0] == '[' && name[len(name)-1] == ']' {
+		name = name[1 : len(name)-1]
+		delimited = true
+	}
+
+	var level Level
+	switch strings.ToUpper(name) {
+	case "TRACE", "TRC":
+		level = LevelTrace
+	case "DEBUG", "DBG":
+		level = LevelDebug
+	case "INFO", "INF":
+		level = LevelInfo
+	case "WARN", "WARNING", "WRN":
+		level = LevelWarn
+	case "ERROR", "ERR":
+		level = LevelError
+	case "FATAL", "PANIC", "CRITICAL", "CRIT":
+		level = LevelFatal
+	default:
+		return LevelInfo, message
+	}
+
+	if !delimited {
+		return level, message
+	}
+	return level, rest
+}
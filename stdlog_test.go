@@ -0,0 +1,92 @@
+package logx_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/cmstar/go-logx"
+	"github.com/cmstar/go-logx/logxtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdLogWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		flags  int
+	}{
+		{"none", "", 0},
+		{"std", "", log.LstdFlags},
+		{"prefix", "[p] ", log.LstdFlags},
+		{"all", "[p] ", log.LstdFlags | log.Lmicroseconds | log.Lshortfile | log.LUTC},
+		{"long", "", log.Llongfile},
+		{"msgprefix", "[p] ", log.LstdFlags | log.Lshortfile | log.Lmsgprefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := logxtest.NewRecorder()
+			w := &logx.StdLogWriter{Logger: r, Level: logx.LevelWarn, Prefix: tt.prefix, Flags: tt.flags}
+			l := log.New(w, tt.prefix, tt.flags)
+
+			l.Print("a: b")
+			l.Printf("c %d\n", 1)
+			l.Println("x\ny")
+
+			assert.Equal(t, "WARN a: b\nWARN c 1\nWARN x\ny\n", r.String())
+		})
+	}
+}
+
+func TestStdLogWriter_detectLevel(t *testing.T) {
+	r := logxtest.NewRecorder()
+	l := logx.NewStdLogLogger(r, 0)
+
+	l.Print("trace: t")
+	l.Print("[DEBUG] d")
+	l.Print("info: i")
+	l.Print("Warning:  w")
+	l.Print("[ERR] e")
+	l.Print("[WARN]: w2")
+	l.Print("panic: p")
+	l.Print("something")
+	l.Print("error:")
+	l.Print("")
+
+	// Words without delimiters decide the level, but they are part of the message.
+	l.Print("error connecting to db")
+	l.Print("Warning")
+	l.Print("[ERR")
+
+	// StdLogger writes a space after the level even if the message is empty.
+	want := "TRACE t\nDEBUG d\nINFO i\nWARN w\nERROR e\nWARN w2\nFATAL p\nINFO something\nERROR \nINFO \n" +
+		"ERROR error connecting to db\nWARN Warning\nINFO [ERR\n"
+	assert.Equal(t, want, r.String())
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	out, prefix, flags := log.Writer(), log.Prefix(), log.Flags()
+	defer func() {
+		log.SetOutput(out)
+		log.SetPrefix(prefix)
+		log.SetFlags(flags)
+	}()
+
+	buf := new(bytes.Buffer)
+	log.SetOutput(buf)
+	log.SetPrefix("p ")
+	log.SetFlags(log.Lshortfile)
+
+	r := logxtest.NewRecorder()
+	restore := logx.RedirectStdLog(r)
+	log.Print("[warn] w")
+	restore()
+	log.Print("x")
+
+	a := assert.New(t)
+	a.Equal("WARN w\n", r.String())
+	a.Regexp(`^p stdlog_test\.go:\d+: x\n$`, buf.String())
+	a.Equal("p ", log.Prefix())
+	a.Equal(log.Lshortfile, log.Flags())
+}
@@ -69,6 +69,11 @@ func (logger *StdLogger) Log(level Level, message string, keyValues ...interface
 	return nil
 }
 
+// Enabled implements LevelEnabler.Enabled(), all levels are enabled.
+func (logger *StdLogger) Enabled(level Level) bool {
+	return true
+}
+
 // LogFn implements Logger.LogFn().
 func (logger *StdLogger) LogFn(level Level, messageFactory func() (string, []interface{})) error {
 	message, keyValues := messageFactory()